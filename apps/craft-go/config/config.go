@@ -0,0 +1,82 @@
+// Package config resolves craft-go runtime settings from the environment.
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+// Record store backends selectable through RECORD_STORE.
+const (
+	StoreMemory = "memory"
+	StoreBolt   = "bolt"
+)
+
+// Config holds the runtime settings for the Go backend.
+type Config struct {
+	// Port is the HTTP listen port (PORT, default 4000).
+	Port string
+	// GRPCPort is the gRPC listen port (GRPC_PORT, default 50051).
+	GRPCPort string
+	// RecordStore selects the record backend (RECORD_STORE, default memory).
+	RecordStore string
+	// DataDir is where file-backed stores keep their data (DATA_DIR, default data).
+	DataDir string
+	// SeedCount is how many records are generated at startup when the store
+	// is empty (RECORD_SEED_COUNT, default 1000, 0 disables seeding).
+	SeedCount int
+	// Seed makes the startup dataset reproducible (RECORD_SEED, default 0
+	// picks a random seed that is logged at startup).
+	Seed int64
+	// GenerationHistory is how many generation runs /api-go/records/stats
+	// retains (GENERATION_HISTORY_SIZE, default 100).
+	GenerationHistory int
+	// GeneratorProfile names the generator profile used when a request names
+	// none (GENERATOR_PROFILE, default full).
+	GeneratorProfile string
+	// GraphQLMaxComplexity bounds the records times fields a GraphQL
+	// operation may request (GRAPHQL_MAX_COMPLEXITY, default 100000).
+	GraphQLMaxComplexity int
+	// ProfilesDir holds extra YAML or JSON generator profiles loaded at
+	// startup (GENERATOR_PROFILES_DIR, default none).
+	ProfilesDir string
+}
+
+// Load reads the configuration from environment variables, applying defaults.
+func Load() Config {
+	return Config{
+		Port:                 getEnv("PORT", "4000"),
+		GRPCPort:             getEnv("GRPC_PORT", "50051"),
+		RecordStore:          getEnv("RECORD_STORE", StoreMemory),
+		DataDir:              getEnv("DATA_DIR", "data"),
+		SeedCount:            getEnvInt("RECORD_SEED_COUNT", 1000),
+		Seed:                 getEnvInt64("RECORD_SEED", 0),
+		GenerationHistory:    getEnvInt("GENERATION_HISTORY_SIZE", 100),
+		GeneratorProfile:     getEnv("GENERATOR_PROFILE", "full"),
+		ProfilesDir:          getEnv("GENERATOR_PROFILES_DIR", ""),
+		GraphQLMaxComplexity: getEnvInt("GRAPHQL_MAX_COMPLEXITY", 100000),
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getEnvInt64(key string, fallback int64) int64 {
+	value, err := strconv.ParseInt(os.Getenv(key), 10, 64)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
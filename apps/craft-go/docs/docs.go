@@ -24,6 +24,46 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api-go/graphql": {
+            "post": {
+                "description": "Selects only the record fields a client needs. ` + "`" + `records(first, after, sort, state, city, lastName, zipcode, q, minIncome, maxIncome)` + "`" + ` returns a connection with ` + "`" + `totalCount` + "`" + `, ` + "`" + `edges { cursor node }` + "`" + `, ` + "`" + `nodes` + "`" + ` and ` + "`" + `pageInfo` + "`" + `; ` + "`" + `record(UID)` + "`" + ` returns one record. Record, Address, Phone and Company fields use the JSON names of the REST API. Operations are rejected before execution when the requested records times the selected fields exceeds the complexity limit. Errors are reported in the ` + "`" + `errors` + "`" + ` array with status 200; GET accepts ` + "`" + `query` + "`" + `, ` + "`" + `operationName` + "`" + ` and JSON-encoded ` + "`" + `variables` + "`" + ` parameters.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Records"
+                ],
+                "summary": "Query records with GraphQL",
+                "parameters": [
+                    {
+                        "description": "GraphQL request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/graphqlapi.Request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GraphQLResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/api-go/health": {
             "get": {
                 "description": "Returns the health status for the Go backend.",
@@ -46,9 +86,10 @@ const docTemplate = `{
         },
         "/api-go/records": {
             "get": {
-                "description": "Returns a generated list of records. Use the ` + "`" + `limit` + "`" + ` query parameter to control count.",
+                "description": "Filters, sorts and pages the stored dataset without modifying it. Without ` + "`" + `pageSize` + "`" + ` up to ` + "`" + `limit` + "`" + ` matching records are returned. Use POST /api-go/records/seed to regenerate the dataset.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/x-ndjson"
                 ],
                 "tags": [
                     "Records"
@@ -58,9 +99,100 @@ const docTemplate = `{
                     {
                         "type": "integer",
                         "default": 1000,
-                        "description": "Maximum number of records to return (1-1000000)",
+                        "description": "Maximum number of records to return when pageSize is not set (1-1000000)",
                         "name": "limit",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "1-based page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Records per page (0-1000000); 0 returns all matches",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "lastName,-totalHouseholdIncome",
+                        "description": "Comma-separated sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exact state match, case-insensitive",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exact city match, case-insensitive",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exact last name match, case-insensitive",
+                        "name": "lastName",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exact zipcode match",
+                        "name": "zipcode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum total household income",
+                        "name": "minIncome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum total household income",
+                        "name": "maxIncome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Case-insensitive substring match on name, address and email",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Stream the response: json keeps the RecordsResponse shape, ndjson writes one record per line (also selected by Accept: application/x-ndjson)",
+                        "name": "stream",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "1",
+                            "2"
+                        ],
+                        "type": "string",
+                        "default": "2",
+                        "description": "Record representation: 2 (canonical Record) or 1 (legacy UserRecord); also read from X-Record-Version",
+                        "name": "version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "UID,firstName,address.city",
+                        "description": "Comma-separated record fields to return, with dots for nested fields; omit for every field",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -77,35 +209,42 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api-go/records/generate": {
-            "get": {
-                "description": "Generates fake records in-memory and returns them immediately.",
+            },
+            "post": {
+                "description": "Stores a new record. A UID is assigned when the body omits one.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Records"
                 ],
-                "summary": "Generate records",
+                "summary": "Create record",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Number of records to generate",
-                        "name": "count",
+                        "description": "Record to create",
+                        "name": "record",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Record"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "example": "UID,firstName,address.city",
+                        "description": "Comma-separated record fields to return, with dots for nested fields; omit for every field",
+                        "name": "fields",
                         "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.UserRecord"
-                            }
+                            "$ref": "#/definitions/models.Record"
                         }
                     },
                     "400": {
@@ -113,58 +252,182 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/api-go/records/time": {
+        "/api-go/records/aggregate": {
             "get": {
-                "description": "Returns the latest record generation time in milliseconds.",
+                "description": "Groups the stored dataset and computes metrics per group, largest groups first. Grouping by ` + "`" + `companyName` + "`" + ` or ` + "`" + `companyPosition` + "`" + ` aggregates one row per employer entry, so ` + "`" + `groupBy=companyName\u0026limit=10` + "`" + ` lists the top employers. Results are cached until the dataset changes.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Records"
                 ],
-                "summary": "Get generation time",
+                "summary": "Aggregate records",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "state",
+                        "description": "Comma-separated group fields: state, city, zipcode, lastName, companyName, companyPosition; omit for one group over the whole dataset",
+                        "name": "groupBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "count,avgIncome,p90Income",
+                        "description": "Comma-separated metrics: count, sum/avg/min/max or pNN followed by Income or Salary, salaryHistogram",
+                        "name": "metrics",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Keep only the largest N groups; 0 keeps all",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "default": 10000,
+                        "description": "Salary histogram bucket width",
+                        "name": "bucketSize",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.GenerationTimeResponse"
+                            "$ref": "#/definitions/services.AggregateResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api-go/records/{UID}": {
+        "/api-go/records/export": {
             "get": {
-                "description": "Returns one record matching the provided UID.",
+                "description": "Streams the records matching the list filters as a file download, as they are read from the store. CSV and XLSX flatten address, phone and salary into columns such as ` + "`" + `address.city` + "`" + ` and ` + "`" + `salary[0].companyName` + "`" + `, with one set of salary columns per company slot; NDJSON keeps the nested record shape. See the recordio package for the full column mapping.",
                 "produces": [
-                    "application/json"
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
                     "Records"
                 ],
-                "summary": "Get record by UID",
+                "summary": "Export records",
                 "parameters": [
                     {
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "xlsx"
+                        ],
                         "type": "string",
-                        "description": "Record UID",
-                        "name": "UID",
-                        "in": "path",
-                        "required": true
+                        "default": "csv",
+                        "description": "File format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of records to export when pageSize is not set (1-1000000); omit to export every match",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "1-based page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Records per page; 0 exports all matches",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "lastName,-totalHouseholdIncome",
+                        "description": "Comma-separated sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exact state match, case-insensitive",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exact city match, case-insensitive",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exact last name match, case-insensitive",
+                        "name": "lastName",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exact zipcode match",
+                        "name": "zipcode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum total household income",
+                        "name": "minIncome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum total household income",
+                        "name": "maxIncome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Case-insensitive substring match on name, address and email",
+                        "name": "q",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.UserRecord"
+                            "type": "file"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -172,22 +435,58 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/records": {
+        "/api-go/records/generate": {
             "get": {
-                "description": "Returns a generated list of records. Use the ` + "`" + `limit` + "`" + ` query parameter to control count.",
+                "description": "Generates fake records in-memory and returns them immediately without storing them.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Records"
                 ],
-                "summary": "List records",
+                "summary": "Generate records",
                 "parameters": [
                     {
                         "type": "integer",
-                        "default": 1000,
-                        "description": "Maximum number of records to return (1-1000000)",
-                        "name": "limit",
+                        "default": 10,
+                        "description": "Number of records to generate (0-1000000)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Generator seed; omit for a random seed reported in X-Record-Seed",
+                        "name": "seed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Generator profile, see GET /api-go/records/profiles; omit for the server default",
+                        "name": "profile",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Locale of names, addresses, phones and salary currency, such as de-DE; see GET /api-go/records/profiles",
+                        "name": "locale",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "1",
+                            "2"
+                        ],
+                        "type": "string",
+                        "default": "2",
+                        "description": "Record representation: 2 (canonical Record) or 1 (legacy UserRecord); also read from X-Record-Version",
+                        "name": "version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "UID,firstName,address.city",
+                        "description": "Comma-separated record fields to return, with dots for nested fields; omit for every field",
+                        "name": "fields",
                         "in": "query"
                     }
                 ],
@@ -195,7 +494,16 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.RecordsResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Record"
+                            }
+                        },
+                        "headers": {
+                            "X-Record-Seed": {
+                                "type": "integer",
+                                "description": "Seed used to generate the records"
+                            }
                         }
                     },
                     "400": {
@@ -207,19 +515,862 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/records/generate": {
-            "get": {
-                "description": "Compatibility route for frontend calls that are not implemented in Go.",
+        "/api-go/records/import": {
+            "post": {
+                "description": "Validates every line of the body against the record model and, only when all lines are valid, writes them in one atomic step. ` + "`" + `merge` + "`" + ` upserts by UID and keeps other records; ` + "`" + `replace` + "`" + ` swaps the whole dataset, and is rejected when the body holds no records unless ` + "`" + `allowEmpty=true` + "`" + `. CSV input needs a header row using the export column names (any order, any subset); NDJSON holds one record object per line. Records without a UID get one assigned. With ` + "`" + `dryRun=true` + "`" + ` nothing is written. Invalid input returns a 422 problem whose ` + "`" + `errors` + "`" + ` name the failing lines, such as ` + "`" + `line 12` + "`" + `.",
+                "consumes": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Compatibility"
+                    "Records"
+                ],
+                "summary": "Import records",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Body format; defaults from Content-Type",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "merge",
+                            "replace"
+                        ],
+                        "type": "string",
+                        "default": "merge",
+                        "description": "How to combine with the stored dataset",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Validate and report without writing",
+                        "name": "dryRun",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Let a replace import without records empty the dataset; otherwise it is rejected",
+                        "name": "allowEmpty",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Compatibility placeholder",
                 "responses": {
-                    "501": {
-                        "description": "Not Implemented",
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/services.ImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-go/records/jobs": {
+            "post": {
+                "description": "Starts regenerating the stored dataset like POST /api-go/records/seed, but returns at once. Follow the job with GET /api-go/records/jobs/{id} or its event stream; the dataset is replaced only when the job completes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Records"
+                ],
+                "summary": "Start a generation job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1000,
+                        "description": "Number of records to generate (1-1000000)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Generator seed; omit for a random seed reported in the job",
+                        "name": "seed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Generator profile, see GET /api-go/records/profiles; omit for the server default",
+                        "name": "profile",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Locale of names, addresses, phones and salary currency, such as de-DE; see GET /api-go/records/profiles",
+                        "name": "locale",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/services.GenerationJob"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the job status"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds to wait before starting another job"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api-go/records/jobs/{id}": {
+            "get": {
+                "description": "Returns the status and progress of a generation job. Finished jobs are kept until 100 newer jobs have finished.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Records"
+                ],
+                "summary": "Get a generation job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/services.GenerationJob"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Stops a running job. The stored dataset is left as it was.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Records"
+                ],
+                "summary": "Cancel a generation job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/services.GenerationJob"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-go/records/jobs/{id}/events": {
+            "get": {
+                "description": "Server-Sent Events with the job as JSON data: a ` + "`" + `progress` + "`" + ` event on every change while it runs, then one ` + "`" + `completed` + "`" + `, ` + "`" + `failed` + "`" + ` or ` + "`" + `canceled` + "`" + ` event before the stream ends. Idle streams receive a comment every 15 seconds.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Records"
+                ],
+                "summary": "Stream generation job progress",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/services.GenerationJob"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-go/records/profiles": {
+            "get": {
+                "description": "Returns the generator profiles and locales that /api-go/records/generate and /api-go/records/seed accept through ` + "`" + `profile` + "`" + ` and ` + "`" + `locale` + "`" + `, and the profile used when none is named.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Records"
+                ],
+                "summary": "List generator profiles and locales",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ProfilesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-go/records/search": {
+            "get": {
+                "description": "Matches every word of ` + "`" + `q` + "`" + ` against first and last name, email, street, city, state, zipcode and employer name and position. Each word also matches longer words it is a prefix of, so ` + "`" + `smi den` + "`" + ` finds Smith in Denver. Hits are ranked by field weight and whole-word matches, and carry byte offsets of the matched words for highlighting.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Records"
+                ],
+                "summary": "Search records",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "smith denver",
+                        "description": "Search words",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Maximum number of hits (1-1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "UID,firstName,address.city",
+                        "description": "Comma-separated record fields to return, with dots for nested fields; omit for every field",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-go/records/seed": {
+            "post": {
+                "description": "Replaces the stored dataset with ` + "`" + `count` + "`" + ` generated records. Existing UIDs become invalid.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Records"
+                ],
+                "summary": "Regenerate dataset",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1000,
+                        "description": "Number of records to generate (1-1000000)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Generator seed; omit for a random seed reported in the response",
+                        "name": "seed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Generator profile, see GET /api-go/records/profiles; omit for the server default",
+                        "name": "profile",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Locale of names, addresses, phones and salary currency, such as de-DE; see GET /api-go/records/profiles",
+                        "name": "locale",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SeedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-go/records/stats": {
+            "get": {
+                "description": "Returns recent record generation runs, oldest first, with duration, record count, throughput and seed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Records"
+                ],
+                "summary": "Get generation stats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GenerationStatsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-go/records/time": {
+            "get": {
+                "description": "Returns the duration of the latest record generation in milliseconds, or 0 before the first run.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Records"
+                ],
+                "summary": "Get generation time",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GenerationTimeResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-go/records/total-income/{UID}": {
+            "get": {
+                "description": "Returns the sum of ` + "`" + `annualSalary` + "`" + ` across the record's companies as a bare JSON number, matching the NestJS backend. Unknown UIDs return 404 where NestJS returns 500.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Records"
+                ],
+                "summary": "Get total income",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Record UID",
+                        "name": "UID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "number"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-go/records/{UID}": {
+            "get": {
+                "description": "Returns one record matching the provided UID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Records"
+                ],
+                "summary": "Get record by UID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Record UID",
+                        "name": "UID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "1",
+                            "2"
+                        ],
+                        "type": "string",
+                        "default": "2",
+                        "description": "Record representation: 2 (canonical Record) or 1 (legacy UserRecord); also read from X-Record-Version",
+                        "name": "version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "UID,firstName,address.city",
+                        "description": "Comma-separated record fields to return, with dots for nested fields; omit for every field",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Record"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces the record matching the provided UID with the request body.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Records"
+                ],
+                "summary": "Replace record",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Record UID",
+                        "name": "UID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Replacement record",
+                        "name": "record",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Record"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "example": "UID,firstName,address.city",
+                        "description": "Comma-separated record fields to return, with dots for nested fields; omit for every field",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Record"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes the record matching the provided UID.",
+                "tags": [
+                    "Records"
+                ],
+                "summary": "Delete record",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Record UID",
+                        "name": "UID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Merges the JSON body into the record matching the provided UID as a JSON merge patch (RFC 7396): objects are merged field by field, arrays are replaced whole and null clears a field.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Records"
+                ],
+                "summary": "Patch record",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Record UID",
+                        "name": "UID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "record",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Record"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "example": "UID,firstName,address.city",
+                        "description": "Comma-separated record fields to return, with dots for nested fields; omit for every field",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Record"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-go/ws/records": {
+            "get": {
+                "description": "WebSocket. Each text message is a JSON change (` + "`" + `created` + "`" + `, ` + "`" + `updated` + "`" + `, ` + "`" + `deleted` + "`" + `, ` + "`" + `regenerated` + "`" + ` or ` + "`" + `merged` + "`" + `) or a notice (` + "`" + `subscribed` + "`" + `, ` + "`" + `dropped` + "`" + `, ` + "`" + `error` + "`" + `). Narrow the feed with ` + "`" + `state` + "`" + ` and ` + "`" + `uid` + "`" + ` query parameters or by sending ` + "`" + `{\"action\":\"subscribe\",\"states\":[...],\"uids\":[...]}` + "`" + `. A client that falls 256 changes behind loses changes and receives a ` + "`" + `dropped` + "`" + ` notice with their count; refetch the records it watches.",
+                "tags": [
+                    "Records"
+                ],
+                "summary": "Record change feed",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only changes to records in these states",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only changes to these records",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/repository.Change"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/records": {
+            "get": {
+                "description": "Filters, sorts and pages the stored dataset without modifying it. Without ` + "`" + `pageSize` + "`" + ` up to ` + "`" + `limit` + "`" + ` matching records are returned. Use POST /api-go/records/seed to regenerate the dataset.",
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Records"
+                ],
+                "summary": "List records",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1000,
+                        "description": "Maximum number of records to return when pageSize is not set (1-1000000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "1-based page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Records per page (0-1000000); 0 returns all matches",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "lastName,-totalHouseholdIncome",
+                        "description": "Comma-separated sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exact state match, case-insensitive",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exact city match, case-insensitive",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exact last name match, case-insensitive",
+                        "name": "lastName",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exact zipcode match",
+                        "name": "zipcode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum total household income",
+                        "name": "minIncome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum total household income",
+                        "name": "maxIncome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Case-insensitive substring match on name, address and email",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Stream the response: json keeps the RecordsResponse shape, ndjson writes one record per line (also selected by Accept: application/x-ndjson)",
+                        "name": "stream",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "1",
+                            "2"
+                        ],
+                        "type": "string",
+                        "default": "2",
+                        "description": "Record representation: 2 (canonical Record) or 1 (legacy UserRecord); also read from X-Record-Version",
+                        "name": "version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "UID,firstName,address.city",
+                        "description": "Comma-separated record fields to return, with dots for nested fields; omit for every field",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RecordsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/records/generate": {
+            "get": {
+                "description": "Compatibility route for frontend calls that are not implemented in Go.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Compatibility"
+                ],
+                "summary": "Compatibility placeholder",
+                "responses": {
+                    "501": {
+                        "description": "Not Implemented",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -229,7 +1380,7 @@ const docTemplate = `{
         },
         "/api/records/time": {
             "get": {
-                "description": "Returns the latest record generation time in milliseconds.",
+                "description": "Returns the duration of the latest record generation in milliseconds, or 0 before the first run.",
                 "produces": [
                     "application/json"
                 ],
@@ -247,6 +1398,41 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/records/total-income/{UID}": {
+            "get": {
+                "description": "Returns the sum of ` + "`" + `annualSalary` + "`" + ` across the record's companies as a bare JSON number, matching the NestJS backend. Unknown UIDs return 404 where NestJS returns 500.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Records"
+                ],
+                "summary": "Get total income",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Record UID",
+                        "name": "UID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "number"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/api/records/{UID}": {
             "get": {
                 "description": "Returns one record matching the provided UID.",
@@ -264,13 +1450,37 @@ const docTemplate = `{
                         "name": "UID",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "1",
+                            "2"
+                        ],
+                        "type": "string",
+                        "default": "2",
+                        "description": "Record representation: 2 (canonical Record) or 1 (legacy UserRecord); also read from X-Record-Version",
+                        "name": "version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "UID,firstName,address.city",
+                        "description": "Comma-separated record fields to return, with dots for nested fields; omit for every field",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.UserRecord"
+                            "$ref": "#/definitions/models.Record"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "404": {
@@ -304,41 +1514,239 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "graphqlapi.Request": {
+            "type": "object",
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string",
+                    "example": "{ records(first: 25, state: \"Colorado\") { totalCount nodes { UID firstName lastName } } }"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
         "handlers.ErrorResponse": {
             "type": "object",
             "properties": {
-                "error": {
+                "correlationId": {
+                    "description": "CorrelationID matches the X-Request-ID response header.",
+                    "type": "string",
+                    "example": "4f9c2a7be0d14c6d8a3e51f0b2c7d9e6"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "Invalid record: firstName is required; salary[0].annualSalary must be gte=0"
+                },
+                "errors": {
+                    "description": "Errors lists the invalid fields or parameters, if any.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/repository.FieldError"
+                    }
+                },
+                "instance": {
+                    "description": "Instance is the request path.",
+                    "type": "string",
+                    "example": "/api-go/records"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 422
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Validation failed"
+                },
+                "type": {
+                    "description": "Type identifies the kind of problem: bad-request, not-found, conflict,\nvalidation, limit-exceeded, internal, not-implemented or unavailable.",
+                    "type": "string",
+                    "example": "https://jeffreysanford.us/problems/validation"
+                }
+            }
+        },
+        "handlers.GenerationStatsResponse": {
+            "type": "object",
+            "properties": {
+                "runs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GenerationRun"
+                    }
+                }
+            }
+        },
+        "handlers.GenerationTimeResponse": {
+            "type": "object",
+            "properties": {
+                "generationTime": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "handlers.GraphQLError": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "query complexity 250300 exceeds the limit of 100000; request fewer records or fields"
+                }
+            }
+        },
+        "handlers.GraphQLResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.GraphQLError"
+                    }
+                }
+            }
+        },
+        "handlers.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "OK"
+                }
+            }
+        },
+        "handlers.LocaleInfo": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Deutsch (Deutschland)"
+                },
+                "tag": {
+                    "type": "string",
+                    "example": "de-DE"
+                }
+            }
+        },
+        "handlers.PageLinks": {
+            "type": "object",
+            "properties": {
+                "next": {
+                    "type": "string",
+                    "example": "/api-go/records?page=3\u0026pageSize=25"
+                },
+                "prev": {
+                    "type": "string",
+                    "example": "/api-go/records?page=1\u0026pageSize=25"
+                },
+                "self": {
                     "type": "string",
-                    "example": "Invalid limit parameter"
+                    "example": "/api-go/records?page=2\u0026pageSize=25"
                 }
             }
         },
-        "handlers.GenerationTimeResponse": {
+        "handlers.ProfileInfo": {
             "type": "object",
             "properties": {
-                "generationTime": {
-                    "type": "integer",
-                    "example": 42
+                "coherent": {
+                    "description": "Coherent profiles draw city, state, zip and area code together from a\nbundled gazetteer.",
+                    "type": "boolean",
+                    "example": true
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Required fields plus a few optional ones, often left empty; zero or one employer."
+                },
+                "name": {
+                    "type": "string",
+                    "example": "sparse"
                 }
             }
         },
-        "handlers.HealthResponse": {
+        "handlers.ProfilesResponse": {
             "type": "object",
             "properties": {
-                "status": {
+                "default": {
                     "type": "string",
-                    "example": "OK"
+                    "example": "full"
+                },
+                "locales": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.LocaleInfo"
+                    }
+                },
+                "profiles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.ProfileInfo"
+                    }
                 }
             }
         },
         "handlers.RecordsResponse": {
             "type": "object",
             "properties": {
+                "links": {
+                    "$ref": "#/definitions/handlers.PageLinks"
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "pageSize": {
+                    "type": "integer",
+                    "example": 25
+                },
                 "records": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.UserRecord"
+                        "$ref": "#/definitions/models.Record"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 1000
+                }
+            }
+        },
+        "handlers.SearchResponse": {
+            "type": "object",
+            "properties": {
+                "hits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/repository.SearchHit"
                     }
+                },
+                "query": {
+                    "type": "string",
+                    "example": "smith denver"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "handlers.SeedResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "seed": {
+                    "type": "integer",
+                    "example": 42
                 }
             }
         },
@@ -359,6 +1767,62 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Company": {
+            "type": "object",
+            "required": [
+                "companyName"
+            ],
+            "properties": {
+                "UID": {
+                    "type": "string"
+                },
+                "annualSalary": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "companyName": {
+                    "type": "string"
+                },
+                "companyPosition": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "Currency is the ISO 4217 code of AnnualSalary; unset means USD.",
+                    "type": "string"
+                },
+                "employeeName": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GenerationRun": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "durationMs": {
+                    "type": "number",
+                    "example": 12.5
+                },
+                "recordsPerSecond": {
+                    "type": "number",
+                    "example": 80000
+                },
+                "seed": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "source": {
+                    "type": "string",
+                    "example": "seed"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
         "models.Phone": {
             "type": "object",
             "properties": {
@@ -385,22 +1849,12 @@ const docTemplate = `{
                 }
             }
         },
-        "models.Salary": {
-            "type": "object",
-            "properties": {
-                "amount": {
-                    "type": "number"
-                },
-                "currency": {
-                    "type": "string"
-                },
-                "year": {
-                    "type": "integer"
-                }
-            }
-        },
-        "models.UserRecord": {
+        "models.Record": {
             "type": "object",
+            "required": [
+                "firstName",
+                "lastName"
+            ],
             "properties": {
                 "UID": {
                     "type": "string"
@@ -408,24 +1862,332 @@ const docTemplate = `{
                 "address": {
                     "$ref": "#/definitions/models.Address"
                 },
+                "avatar": {},
+                "birthDate": {
+                    "type": "string"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
                 "firstName": {
                     "type": "string"
                 },
+                "flicker": {},
                 "lastName": {
                     "type": "string"
                 },
+                "name": {
+                    "type": "string"
+                },
                 "phone": {
                     "$ref": "#/definitions/models.Phone"
                 },
+                "registrationDate": {
+                    "type": "string"
+                },
                 "salary": {
-                    "description": "Using the Salary from craftlibrary.go",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Salary"
+                        "$ref": "#/definitions/models.Company"
                     }
                 },
+                "state": {
+                    "type": "string"
+                },
                 "totalHouseholdIncome": {
-                    "type": "integer"
+                    "type": "number",
+                    "minimum": 0
+                },
+                "zip": {
+                    "type": "string"
+                }
+            }
+        },
+        "repository.Change": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Count is the dataset size after a regeneration, or the number of\nrecords upserted by a merge.",
+                    "type": "integer",
+                    "example": 1000
+                },
+                "record": {
+                    "$ref": "#/definitions/models.Record"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "created",
+                        "updated",
+                        "deleted",
+                        "regenerated",
+                        "merged"
+                    ],
+                    "example": "updated"
+                },
+                "uid": {
+                    "description": "UID and Record identify the record changed: the stored record for\ncreated and updated, the removed one for deleted. Both are empty for\nregenerated, which replaces the whole dataset, and for merged, which\nupserts many records at once.",
+                    "type": "string",
+                    "example": "9a5e0b1e-3f6d-4c2e-8d1a-5b7c9e2f4a60"
+                },
+                "version": {
+                    "description": "Version is the store version after the change; see RecordStore.Version.",
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "repository.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "salary[0].annualSalary"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "must be gte=0"
+                }
+            }
+        },
+        "repository.Highlight": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "field": {
+                    "type": "string",
+                    "example": "address.city"
+                },
+                "start": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "repository.SearchHit": {
+            "type": "object",
+            "properties": {
+                "highlights": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/repository.Highlight"
+                    }
+                },
+                "record": {
+                    "$ref": "#/definitions/models.Record"
+                },
+                "score": {
+                    "type": "number",
+                    "example": 7.5
+                }
+            }
+        },
+        "services.AggregateGroup": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "histogram": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.HistogramBucket"
+                    }
+                },
+                "key": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "metrics": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number",
+                        "format": "float64"
+                    }
+                }
+            }
+        },
+        "services.AggregateResult": {
+            "type": "object",
+            "properties": {
+                "datasetVersion": {
+                    "description": "DatasetVersion is the dataset version the computation started from. A\nwrite that landed during it may already be counted.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "groupBy": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.AggregateGroup"
+                    }
+                },
+                "metrics": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "totalGroups": {
+                    "type": "integer",
+                    "example": 50
+                }
+            }
+        },
+        "services.GenerationJob": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 1000000
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finishedAt": {
+                    "type": "string"
+                },
+                "generated": {
+                    "description": "Generated counts the records generated so far, in steps of 4096.",
+                    "type": "integer",
+                    "example": 409600
+                },
+                "id": {
+                    "type": "string",
+                    "example": "0b5e8f0c-2f4e-4d5c-9a51-3c1f2d7e8a90"
+                },
+                "locale": {
+                    "type": "string",
+                    "example": "de-DE"
+                },
+                "percent": {
+                    "type": "number",
+                    "example": 40.96
+                },
+                "profile": {
+                    "description": "Profile and Locale are the names requested, empty for the defaults.",
+                    "type": "string",
+                    "example": "full"
+                },
+                "recordsPerSecond": {
+                    "type": "number",
+                    "example": 250000
+                },
+                "seed": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "running",
+                        "completed",
+                        "failed",
+                        "canceled"
+                    ],
+                    "example": "running"
+                }
+            }
+        },
+        "services.HistogramBucket": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "from": {
+                    "type": "number",
+                    "example": 50000
+                }
+            }
+        },
+        "services.ImportLineError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "firstName is required"
+                },
+                "line": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "services.ImportMode": {
+            "type": "string",
+            "enum": [
+                "replace",
+                "merge"
+            ],
+            "x-enum-varnames": [
+                "ImportReplace",
+                "ImportMerge"
+            ]
+        },
+        "services.ImportResult": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "Created and Updated count the records that were (or, in a dry run,\nwould be) added and overwritten. Replace imports count every record\nas created and report the dropped dataset size in Removed.",
+                    "type": "integer",
+                    "example": 990
+                },
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "errorCount": {
+                    "description": "ErrorCount counts every rejected line; Errors lists the first 100.",
+                    "type": "integer",
+                    "example": 0
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.ImportLineError"
+                    }
+                },
+                "mode": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/services.ImportMode"
+                        }
+                    ],
+                    "example": "merge"
+                },
+                "read": {
+                    "description": "Read counts the records decoded, valid or not.",
+                    "type": "integer",
+                    "example": 1000
+                },
+                "removed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "updated": {
+                    "type": "integer",
+                    "example": 10
                 }
             }
         }
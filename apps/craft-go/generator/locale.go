@@ -0,0 +1,334 @@
+package generator
+
+import (
+	"bytes"
+	"craft-fusion/craft-go/models"
+	"embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultLocale is the locale whose places coherent profiles draw from when
+// a request names no locale.
+const DefaultLocale = "en-US"
+
+// ErrUnknownLocale is returned when a locale tag is not bundled.
+var ErrUnknownLocale = errors.New("unknown generator locale")
+
+// Every locale is a pair of files: <tag>.yaml with names, formats and
+// currency, and <tag>.csv with the gazetteer of places.
+//
+//go:embed locales
+var localeFiles embed.FS
+
+// Place is one gazetteer entry: a city with the postal codes and telephone
+// area codes that serve it.
+type Place struct {
+	City      string
+	State     string
+	StateCode string
+	// ZipFrom and ZipTo bound the postal codes drawn for the city, as the
+	// number formed by their digits.
+	ZipFrom, ZipTo int
+	AreaCodes      []string
+}
+
+// Locale is the bundled data of one locale.
+type Locale struct {
+	spec   localeSpec
+	places []Place
+}
+
+// localeSpec is the YAML form of a locale. Formats use # for any digit and
+// N for a digit from 2 to 9, so phone numbers and house numbers never start
+// with 0 or 1.
+type localeSpec struct {
+	Tag         string `yaml:"tag"`
+	Name        string `yaml:"name"`
+	CountryCode string `yaml:"countryCode"`
+	Currency    string `yaml:"currency"`
+	// Rate converts the US dollar amounts of profiles into Currency, rounded
+	// to Decimals places.
+	Rate       float64 `yaml:"rate"`
+	Decimals   int     `yaml:"decimals"`
+	PostalCode string  `yaml:"postalCode"`
+	// Phone holds the number format for each area code length; {area} marks
+	// the area code.
+	Phone           map[int]string `yaml:"phone"`
+	FamilyNameFirst bool           `yaml:"familyNameFirst"`
+	// StreetFormats place a house number around {street}. Locales without
+	// streets or names keep the values drawn by the profile.
+	StreetFormats []string `yaml:"streetFormats"`
+	Streets       []string `yaml:"streets"`
+	FirstNames    []string `yaml:"firstNames"`
+	LastNames     []string `yaml:"lastNames"`
+}
+
+var locales = func() map[string]*Locale {
+	locales, err := loadLocales()
+	if err != nil {
+		panic(err)
+	}
+	return locales
+}()
+
+func loadLocales() (map[string]*Locale, error) {
+	names, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		return nil, err
+	}
+	locales := make(map[string]*Locale)
+	for _, entry := range names {
+		tag, ok := strings.CutSuffix(entry.Name(), ".yaml")
+		if !ok {
+			continue
+		}
+		locale, err := loadLocale(tag)
+		if err != nil {
+			return nil, fmt.Errorf("locale %s: %w", tag, err)
+		}
+		locales[strings.ToLower(tag)] = locale
+	}
+	return locales, nil
+}
+
+func loadLocale(tag string) (*Locale, error) {
+	data, err := localeFiles.ReadFile(path.Join("locales", tag+".yaml"))
+	if err != nil {
+		return nil, err
+	}
+	var locale Locale
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&locale.spec); err != nil {
+		return nil, err
+	}
+	if locale.spec.Tag != tag {
+		return nil, fmt.Errorf("tag %q does not match the file name", locale.spec.Tag)
+	}
+	if locale.spec.Rate <= 0 || locale.spec.Currency == "" || locale.spec.CountryCode == "" {
+		return nil, errors.New("countryCode, currency and a positive rate are required")
+	}
+	if (len(locale.spec.FirstNames) == 0) != (len(locale.spec.LastNames) == 0) ||
+		(len(locale.spec.Streets) == 0) != (len(locale.spec.StreetFormats) == 0) {
+		return nil, errors.New("names and streets need both of their lists")
+	}
+
+	data, err = localeFiles.ReadFile(path.Join("locales", tag+".csv"))
+	if err != nil {
+		return nil, err
+	}
+	if locale.places, err = parsePlaces(data); err != nil {
+		return nil, err
+	}
+	zipLimit := int(math.Pow10(strings.Count(locale.spec.PostalCode, "#")))
+	for _, place := range locale.places {
+		if place.ZipTo >= zipLimit {
+			return nil, fmt.Errorf("%s: postal codes exceed %q", place.City, locale.spec.PostalCode)
+		}
+		for _, code := range place.AreaCodes {
+			if _, ok := locale.spec.Phone[len(code)]; !ok {
+				return nil, fmt.Errorf("%s: no phone format for area code %s", place.City, code)
+			}
+		}
+	}
+	return &locale, nil
+}
+
+func parsePlaces(data []byte) ([]Place, error) {
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) < 2 {
+		return nil, errors.New("gazetteer has no places")
+	}
+	places := make([]Place, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		place := Place{City: row[0], State: row[1], StateCode: row[2], AreaCodes: strings.Fields(row[5])}
+		place.ZipFrom, err = strconv.Atoi(row[3])
+		if err == nil {
+			place.ZipTo, err = strconv.Atoi(row[4])
+		}
+		if err != nil || place.ZipFrom > place.ZipTo || len(place.AreaCodes) == 0 {
+			return nil, fmt.Errorf("gazetteer: invalid line %d", i+2)
+		}
+		places = append(places, place)
+	}
+	return places, nil
+}
+
+// LookupLocale returns the bundled locale for tag, ignoring case.
+func LookupLocale(tag string) (*Locale, error) {
+	locale, ok := locales[strings.ToLower(tag)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownLocale, tag)
+	}
+	return locale, nil
+}
+
+// Locales returns every bundled locale ordered by tag.
+func Locales() []*Locale {
+	all := make([]*Locale, 0, len(locales))
+	for _, locale := range locales {
+		all = append(all, locale)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Tag() < all[j].Tag() })
+	return all
+}
+
+// Tag returns the BCP 47 tag used by ?locale=, such as de-DE.
+func (l *Locale) Tag() string {
+	return l.spec.Tag
+}
+
+// Name returns the name of the locale in its own language.
+func (l *Locale) Name() string {
+	return l.spec.Name
+}
+
+// Currency returns the ISO 4217 code of salaries generated for the locale.
+func (l *Locale) Currency() string {
+	return l.spec.Currency
+}
+
+// Places returns the gazetteer of the locale.
+func (l *Locale) Places() []Place {
+	return l.places
+}
+
+// PostalCode formats the postal code numbered zip in the locale's pattern.
+func (l *Locale) PostalCode(zip int) string {
+	digits := []byte(strconv.Itoa(zip))
+	pattern := []byte(l.spec.PostalCode)
+	for i := len(pattern) - 1; i >= 0; i-- {
+		if pattern[i] != '#' {
+			continue
+		}
+		pattern[i] = '0'
+		if len(digits) > 0 {
+			pattern[i], digits = digits[len(digits)-1], digits[:len(digits)-1]
+		}
+	}
+	return string(pattern)
+}
+
+// Zip reports whether zip, ignoring separators, is one of the place's postal
+// codes.
+func (p Place) Zip(zip string) bool {
+	number, err := strconv.Atoi(strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, zip))
+	return err == nil && number >= p.ZipFrom && number <= p.ZipTo
+}
+
+// AreaCode reports whether code is one of the place's area codes.
+func (p Place) AreaCode(code string) bool {
+	for _, areaCode := range p.AreaCodes {
+		if areaCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// apply localizes the populated fields of record: names, street, location,
+// phone country code and the currency of every company. Salaries and the
+// household income are converted from US dollars. Email addresses are kept.
+func (l *Locale) apply(r *rand.Rand, record *models.Record) {
+	if len(l.spec.FirstNames) > 0 {
+		first, last := pick(r, l.spec.FirstNames), pick(r, l.spec.LastNames)
+		replace(&record.FirstName, first)
+		replace(&record.LastName, last)
+		replace(&record.Name, l.fullName(first, last))
+		for i := range record.Salary {
+			replace(&record.Salary[i].EmployeeName, l.fullName(pick(r, l.spec.FirstNames), pick(r, l.spec.LastNames)))
+		}
+	}
+	if len(l.spec.Streets) > 0 {
+		street := digits(r, pick(r, l.spec.StreetFormats))
+		replace(&record.Address.Street, strings.ReplaceAll(street, "{street}", pick(r, l.spec.Streets)))
+	}
+	l.locate(r, record)
+
+	if record.Phone.Number != "" || record.Phone.CountryCode != nil {
+		countryCode := l.spec.CountryCode
+		record.Phone.CountryCode = &countryCode
+	}
+	record.TotalHouseholdIncome = l.convert(record.TotalHouseholdIncome)
+	for i := range record.Salary {
+		currency := l.spec.Currency
+		record.Salary[i].AnnualSalary = l.convert(record.Salary[i].AnnualSalary)
+		record.Salary[i].Currency = &currency
+	}
+}
+
+// locate overwrites the populated location fields of record with one place
+// drawn from r: city, state and postal code of the address and the legacy
+// top-level fields, the phone area code and the area code of the phone
+// number. Fields the profile left empty stay empty.
+func (l *Locale) locate(r *rand.Rand, record *models.Record) {
+	place := l.places[r.Intn(len(l.places))]
+	zip := l.PostalCode(place.ZipFrom + r.Intn(place.ZipTo-place.ZipFrom+1))
+	areaCode := place.AreaCodes[r.Intn(len(place.AreaCodes))]
+	number := strings.ReplaceAll(digits(r, l.spec.Phone[len(areaCode)]), "{area}", areaCode)
+
+	replace(&record.Address.City, place.City)
+	replace(&record.Address.State, place.State)
+	replace(&record.Address.Zipcode, zip)
+	replace(&record.City, place.City)
+	replace(&record.State, place.State)
+	replace(&record.Zip, zip)
+	replace(&record.Phone.Number, number)
+	if record.Phone.AreaCode != nil {
+		record.Phone.AreaCode = &areaCode
+	}
+}
+
+func (l *Locale) fullName(first, last string) string {
+	if l.spec.FamilyNameFirst {
+		return last + " " + first
+	}
+	return first + " " + last
+}
+
+func (l *Locale) convert(amount float64) float64 {
+	scale := math.Pow10(l.spec.Decimals)
+	return math.Round(amount*l.spec.Rate*scale) / scale
+}
+
+// digits replaces the # and N placeholders of format with random digits.
+func digits(r *rand.Rand, format string) string {
+	out := []rune(format)
+	for i, c := range out {
+		switch c {
+		case '#':
+			out[i] = rune('0' + r.Intn(10))
+		case 'N':
+			out[i] = rune('2' + r.Intn(8))
+		}
+	}
+	return string(out)
+}
+
+func pick(r *rand.Rand, values []string) string {
+	return values[r.Intn(len(values))]
+}
+
+func replace(value *string, with string) {
+	if *value != "" {
+		*value = with
+	}
+}
@@ -0,0 +1,147 @@
+package generator
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGazetteerCoversEveryState(t *testing.T) {
+	t.Parallel()
+	states := make(map[string]bool)
+	us, err := LookupLocale(DefaultLocale)
+	require.NoError(t, err)
+	for _, place := range us.Places() {
+		states[place.StateCode] = true
+		assert.Len(t, place.StateCode, 2, place.City)
+		for _, code := range place.AreaCodes {
+			assert.Len(t, code, 3, place.City)
+		}
+	}
+	assert.Len(t, states, 50)
+}
+
+func TestCoherentProfilesGenerateConsistentLocations(t *testing.T) {
+	t.Parallel()
+	places := make(map[string][]Place)
+	us, err := LookupLocale(DefaultLocale)
+	require.NoError(t, err)
+	for _, place := range us.Places() {
+		key := place.City + "|" + place.State
+		places[key] = append(places[key], place)
+	}
+
+	full, err := Builtin().Get("full")
+	require.NoError(t, err)
+	require.True(t, full.Coherent())
+	faker := gofakeit.New(3)
+	cities := make(map[string]bool)
+	for i := 0; i < 10000; i++ {
+		record := full.Record(faker)
+		candidates := places[record.Address.City+"|"+record.Address.State]
+		require.NotEmpty(t, candidates, "%s, %s is not in the gazetteer", record.Address.City, record.Address.State)
+		require.NotNil(t, record.Phone.AreaCode)
+		areaCode := *record.Phone.AreaCode
+
+		consistent := false
+		for _, place := range candidates {
+			consistent = consistent || (place.Zip(record.Address.Zipcode) && place.AreaCode(areaCode))
+		}
+		require.True(t, consistent, "%s, %s %s with area code %s", record.Address.City,
+			record.Address.State, record.Address.Zipcode, areaCode)
+		require.True(t, strings.HasPrefix(record.Phone.Number, "("+areaCode+") "), record.Phone.Number)
+		cities[record.Address.City] = true
+	}
+	assert.Greater(t, len(cities), 50, "locations are drawn across the gazetteer")
+}
+
+func TestCoherentProfilesKeepNullLocationsEmpty(t *testing.T) {
+	t.Parallel()
+	sparse, err := Builtin().Get("sparse")
+	require.NoError(t, err)
+	faker := gofakeit.New(9)
+	empty := 0
+	for i := 0; i < 200; i++ {
+		record := sparse.Record(faker)
+		assert.Empty(t, record.Address.Zipcode)
+		assert.Nil(t, record.Phone.AreaCode)
+		if record.Address.City == "" {
+			empty++
+		}
+	}
+	assert.Greater(t, empty, 0)
+}
+
+func TestLocalesLocalizeRecords(t *testing.T) {
+	t.Parallel()
+	full, err := Builtin().Get("full")
+	require.NoError(t, err)
+	postal := map[string]*regexp.Regexp{
+		"de-DE": regexp.MustCompile(`^\d{5}$`),
+		"en-US": regexp.MustCompile(`^\d{5}$`),
+		"fr-FR": regexp.MustCompile(`^\d{5}$`),
+		"ja-JP": regexp.MustCompile(`^\d{3}-\d{4}$`),
+	}
+	countryCodes := map[string]string{"de-DE": "49", "en-US": "1", "fr-FR": "33", "ja-JP": "81"}
+	currencies := map[string]string{"de-DE": "EUR", "en-US": "USD", "fr-FR": "EUR", "ja-JP": "JPY"}
+
+	require.Len(t, Locales(), len(postal))
+	for _, locale := range Locales() {
+		tag := locale.Tag()
+		places := make(map[string]Place)
+		for _, place := range locale.Places() {
+			places[place.City+"|"+place.State] = place
+		}
+		localized := full.WithLocale(locale)
+		assert.Equal(t, localized.Record(gofakeit.New(5)), localized.Record(gofakeit.New(5)), tag)
+
+		faker := gofakeit.New(11)
+		for i := 0; i < 2000; i++ {
+			record := localized.Record(faker)
+			place, ok := places[record.Address.City+"|"+record.Address.State]
+			require.True(t, ok, "%s: %s, %s is not in the gazetteer", tag, record.Address.City, record.Address.State)
+			assert.Regexp(t, postal[tag], record.Address.Zipcode, tag)
+			assert.True(t, place.Zip(record.Address.Zipcode), "%s: %s in %s", tag, record.Address.Zipcode, place.City)
+			require.NotNil(t, record.Phone.AreaCode)
+			assert.True(t, place.AreaCode(*record.Phone.AreaCode), tag)
+			assert.Contains(t, record.Phone.Number, *record.Phone.AreaCode, tag)
+			require.NotNil(t, record.Phone.CountryCode)
+			assert.Equal(t, countryCodes[tag], *record.Phone.CountryCode)
+			for _, company := range record.Salary {
+				require.NotNil(t, company.Currency)
+				assert.Equal(t, currencies[tag], *company.Currency)
+			}
+		}
+	}
+}
+
+func TestLocaleNamesAndFormats(t *testing.T) {
+	t.Parallel()
+	full, err := Builtin().Get("full")
+	require.NoError(t, err)
+	usd := full.Record(gofakeit.New(2))
+	assert.Nil(t, usd.Salary[0].Currency, "records without a locale keep the legacy shape")
+
+	ja, err := LookupLocale("JA-jp")
+	require.NoError(t, err)
+	record := full.WithLocale(ja).Record(gofakeit.New(2))
+	assert.Contains(t, ja.spec.LastNames, record.LastName)
+	assert.Contains(t, ja.spec.FirstNames, record.FirstName)
+	assert.Contains(t, record.Address.Street, "丁目")
+	assert.Regexp(t, `^0\d{1,2}-\d{3,4}-\d{4}$`, record.Phone.Number)
+	assert.InDelta(t, usd.TotalHouseholdIncome*150, record.TotalHouseholdIncome, 0.5)
+	assert.Equal(t, "160-0001", ja.PostalCode(1600001))
+	assert.Equal(t, "060-0001", ja.PostalCode(600001))
+
+	de, err := LookupLocale("de-DE")
+	require.NoError(t, err)
+	assert.Equal(t, "01067", de.PostalCode(1067))
+	assert.Regexp(t, `^\S+ [2-9]`, full.WithLocale(de).Record(gofakeit.New(2)).Address.Street)
+
+	_, err = LookupLocale("xx-XX")
+	assert.ErrorIs(t, err, ErrUnknownLocale)
+}
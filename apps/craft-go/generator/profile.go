@@ -0,0 +1,279 @@
+// Package generator builds mock records from declarative profiles.
+//
+// A profile lists the record fields to fill, in order, each with a gofakeit
+// lookup function (see gofakeit.FuncLookups), its parameters and the
+// probability of leaving the field empty. Field names are the flat column
+// names of the recordio package, such as firstName or address.city. The
+// special field salary generates between min and max companies from its own
+// field list, named relative to the company (companyName, annualSalary).
+//
+//	name: sparse
+//	description: Names and location only.
+//	fields:
+//	  - {field: UID, func: uuid}
+//	  - {field: email, func: email, nullRate: 0.5}
+//	  - {field: totalHouseholdIncome, func: price, params: {min: 100000, max: 500000}}
+//	  - field: salary
+//	    min: 0
+//	    max: 2
+//	    fields:
+//	      - {field: companyName, func: company}
+//
+// A profile with coherent: true then replaces the city, state and zip of
+// every record, and the area code of its phone, with one entry of a bundled
+// gazetteer of US cities, so the location fields agree with each other.
+// Location fields the profile left empty stay empty. WithLocale does the same
+// from the gazetteer of another locale and also localizes names, streets,
+// phone country codes and salary currency; see the locales directory.
+//
+// Generation is deterministic for a given faker seed: fields are drawn in
+// the order listed, and the date and daterange functions are replaced by
+// versions that use the faker's random source instead of the global one.
+package generator
+
+import (
+	"craft-fusion/craft-go/models"
+	"craft-fusion/craft-go/recordio"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+// Spec is the declarative form of a profile, as read from YAML or JSON.
+type Spec struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+	// Coherent draws the location fields of each record together from the
+	// gazetteer.
+	Coherent bool        `json:"coherent,omitempty" yaml:"coherent,omitempty"`
+	Fields   []FieldSpec `json:"fields" yaml:"fields"`
+}
+
+// FieldSpec describes how one field is generated.
+type FieldSpec struct {
+	Field string `json:"field" yaml:"field"`
+	// Func names a gofakeit lookup function. Value sets a constant instead.
+	Func   string         `json:"func,omitempty" yaml:"func,omitempty"`
+	Params map[string]any `json:"params,omitempty" yaml:"params,omitempty"`
+	Value  *string        `json:"value,omitempty" yaml:"value,omitempty"`
+	// NullRate is the probability, from 0 to 1, that the field is left empty.
+	// A plain null key would read as a YAML null.
+	NullRate float64 `json:"nullRate,omitempty" yaml:"nullRate,omitempty"`
+	// Min, Max and Fields describe the companies of the salary field.
+	Min    int         `json:"min,omitempty" yaml:"min,omitempty"`
+	Max    int         `json:"max,omitempty" yaml:"max,omitempty"`
+	Fields []FieldSpec `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// Profile is a compiled Spec.
+type Profile struct {
+	name        string
+	description string
+	coherent    bool
+	locale      *Locale
+	fields      []field
+}
+
+// field generates one value, or a list of companies for salary.
+type field struct {
+	null     float64
+	generate func(r *rand.Rand) (string, error)
+	set      func(record *models.Record, value string) error
+
+	minCompanies, maxCompanies int
+	companyFields              []field
+}
+
+// maxCompanies bounds the companies generated per record.
+const maxCompanies = 20
+
+// Compile validates a spec and prepares it for generation.
+func Compile(spec Spec) (*Profile, error) {
+	if strings.TrimSpace(spec.Name) == "" {
+		return nil, errors.New("profile name is required")
+	}
+	fields, err := compileFields(spec.Fields, "")
+	if err != nil {
+		return nil, fmt.Errorf("profile %q: %w", spec.Name, err)
+	}
+	return &Profile{name: spec.Name, description: spec.Description, coherent: spec.Coherent, fields: fields}, nil
+}
+
+// compileFields compiles a field list. Company fields are compiled with
+// prefix "salary[0]." and generated into a scratch record.
+func compileFields(specs []FieldSpec, prefix string) ([]field, error) {
+	fields := make([]field, 0, len(specs))
+	for _, spec := range specs {
+		compiled, err := compileField(spec, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", prefix+spec.Field, err)
+		}
+		fields = append(fields, compiled)
+	}
+	return fields, nil
+}
+
+func compileField(spec FieldSpec, prefix string) (field, error) {
+	compiled := field{null: spec.NullRate}
+	if spec.NullRate < 0 || spec.NullRate > 1 {
+		return field{}, errors.New("nullRate must be between 0 and 1")
+	}
+
+	if spec.Field == "salary" && prefix == "" {
+		if spec.Min < 0 || spec.Max < spec.Min || spec.Max > maxCompanies {
+			return field{}, fmt.Errorf("company count must satisfy 0 <= min <= max <= %d", maxCompanies)
+		}
+		companyFields, err := compileFields(spec.Fields, "salary[0].")
+		if err != nil {
+			return field{}, err
+		}
+		compiled.minCompanies, compiled.maxCompanies, compiled.companyFields = spec.Min, spec.Max, companyFields
+		return compiled, nil
+	}
+	if strings.HasPrefix(spec.Field, "salary") && prefix == "" {
+		return field{}, errors.New("company fields belong in the fields list of salary")
+	}
+
+	set, err := recordio.Setter(prefix + spec.Field)
+	if err != nil {
+		return field{}, err
+	}
+	compiled.set = set
+
+	switch {
+	case spec.Value != nil && spec.Func != "":
+		return field{}, errors.New("set either func or value")
+	case spec.Value != nil:
+		value := *spec.Value
+		compiled.generate = func(*rand.Rand) (string, error) { return value, nil }
+	default:
+		if compiled.generate, err = lookup(spec.Func, spec.Params); err != nil {
+			return field{}, err
+		}
+	}
+
+	// Generate once so bad parameters fail at load time instead of leaving
+	// fields silently empty.
+	value, err := compiled.generate(rand.New(rand.NewSource(1)))
+	if err == nil {
+		err = compiled.set(&models.Record{}, value)
+	}
+	if err != nil {
+		return field{}, err
+	}
+	return compiled, nil
+}
+
+// lookup resolves a gofakeit function with fixed parameters.
+func lookup(name string, params map[string]any) (func(r *rand.Rand) (string, error), error) {
+	info, ok := seededFuncs[name]
+	if !ok {
+		info = gofakeit.GetFuncLookup(name)
+	}
+	if info == nil {
+		return nil, fmt.Errorf("unknown func %q", name)
+	}
+
+	mapParams := gofakeit.NewMapParams()
+	for key, value := range params {
+		if values, ok := value.([]any); ok {
+			for _, item := range values {
+				mapParams.Add(key, fmt.Sprint(item))
+			}
+			continue
+		}
+		mapParams.Add(key, fmt.Sprint(value))
+	}
+	return func(r *rand.Rand) (string, error) {
+		value, err := info.Generate(r, mapParams, info)
+		if err != nil {
+			return "", err
+		}
+		return formatValue(value), nil
+	}, nil
+}
+
+func formatValue(value any) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(value), 'f', -1, 32)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// Name returns the profile name used by ?profile=.
+func (p *Profile) Name() string {
+	return p.name
+}
+
+// Coherent reports whether the profile draws locations from the gazetteer.
+func (p *Profile) Coherent() bool {
+	return p.coherent
+}
+
+// Description returns the human-readable summary of the profile.
+func (p *Profile) Description() string {
+	return p.description
+}
+
+// Record generates one record from faker.
+func (p *Profile) Record(faker *gofakeit.Faker) models.Record {
+	record := models.Record{Salary: []models.Company{}}
+	for i := range p.fields {
+		p.fields[i].fill(faker.Rand, &record)
+	}
+	switch {
+	case p.locale != nil:
+		p.locale.apply(faker.Rand, &record)
+	case p.coherent:
+		locales[strings.ToLower(DefaultLocale)].locate(faker.Rand, &record)
+	}
+	return record
+}
+
+// WithLocale returns a copy of the profile that localizes every record it
+// generates; see Locale.
+func (p *Profile) WithLocale(locale *Locale) *Profile {
+	localized := *p
+	localized.locale = locale
+	return &localized
+}
+
+// fill draws the field's value into record. The null draw only consumes
+// randomness for fields that can be null, so a profile without null
+// probabilities draws exactly the values of its functions in order.
+func (f *field) fill(r *rand.Rand, record *models.Record) {
+	if f.null > 0 && r.Float64() < f.null {
+		return
+	}
+	if f.set != nil {
+		if value, err := f.generate(r); err == nil {
+			_ = f.set(record, value)
+		}
+		return
+	}
+
+	count := f.minCompanies
+	if f.maxCompanies > f.minCompanies {
+		count += r.Intn(f.maxCompanies - f.minCompanies + 1)
+	}
+	record.Salary = make([]models.Company, count)
+	for i := range record.Salary {
+		var scratch models.Record
+		for j := range f.companyFields {
+			f.companyFields[j].fill(r, &scratch)
+		}
+		if len(scratch.Salary) > 0 {
+			record.Salary[i] = scratch.Salary[0]
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileRejectsInvalidSpecs(t *testing.T) {
+	t.Parallel()
+	value := "1"
+	cases := map[string]Spec{
+		"missing name":    {Fields: []FieldSpec{{Field: "firstName", Func: "firstname"}}},
+		"unknown func":    {Name: "bad", Fields: []FieldSpec{{Field: "firstName", Func: "nosuchfunc"}}},
+		"unknown field":   {Name: "bad", Fields: []FieldSpec{{Field: "nickname", Func: "firstname"}}},
+		"null range":      {Name: "bad", Fields: []FieldSpec{{Field: "email", Func: "email", NullRate: 1.5}}},
+		"func and value":  {Name: "bad", Fields: []FieldSpec{{Field: "email", Func: "email", Value: &value}}},
+		"numeric value":   {Name: "bad", Fields: []FieldSpec{{Field: "totalHouseholdIncome", Func: "firstname"}}},
+		"company bounds":  {Name: "bad", Fields: []FieldSpec{{Field: "salary", Min: 3, Max: 1}}},
+		"company outside": {Name: "bad", Fields: []FieldSpec{{Field: "salary[0].companyName", Func: "company"}}},
+	}
+	for name, spec := range cases {
+		_, err := Compile(spec)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestBuiltinProfilesAreDeterministic(t *testing.T) {
+	t.Parallel()
+	for _, profile := range Builtin().Profiles() {
+		first := profile.Record(gofakeit.New(42))
+		second := profile.Record(gofakeit.New(42))
+		assert.Equal(t, first, second, profile.Name())
+		assert.NotEmpty(t, first.UID, profile.Name())
+	}
+}
+
+func TestBuiltinProfilesShapeRecords(t *testing.T) {
+	t.Parallel()
+	registry := Builtin()
+	assert.Equal(t, DefaultProfile, registry.Default().Name())
+
+	full, err := registry.Get("full")
+	require.NoError(t, err)
+	faker := gofakeit.New(7)
+	for i := 0; i < 20; i++ {
+		record := full.Record(faker)
+		assert.NotEmpty(t, record.Email)
+		assert.NotEmpty(t, record.Phone.Number)
+		require.NotNil(t, record.Phone.CountryCode)
+		assert.Equal(t, "1", *record.Phone.CountryCode)
+		assert.GreaterOrEqual(t, len(record.Salary), 1)
+		assert.LessOrEqual(t, len(record.Salary), 3)
+	}
+
+	classic, err := registry.Get("classic")
+	require.NoError(t, err)
+	record := classic.Record(gofakeit.New(7))
+	assert.Empty(t, record.Email)
+	require.Len(t, record.Salary, 1)
+	assert.NotEmpty(t, record.Salary[0].CompanyName)
+
+	sparse, err := registry.Get("sparse")
+	require.NoError(t, err)
+	emails := 0
+	for i := 0; i < 100; i++ {
+		if sparse.Record(faker).Email != "" {
+			emails++
+		}
+	}
+	assert.Greater(t, emails, 10)
+	assert.Less(t, emails, 90, "nullRate leaves fields empty")
+
+	_, err = registry.Get("missing")
+	assert.ErrorIs(t, err, ErrUnknownProfile)
+}
+
+func TestLoadRegistryReadsProfileFiles(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "names.yaml"), []byte(`
+name: names
+description: Names only.
+fields:
+  - {field: firstName, func: firstname}
+  - {field: lastName, value: Doe}
+`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sparse.json"),
+		[]byte(`{"name": "sparse", "fields": [{"field": "UID", "func": "uuid"}]}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o644))
+
+	registry, err := LoadRegistry(dir, "names")
+	require.NoError(t, err)
+	assert.Equal(t, "names", registry.Default().Name())
+	record := registry.Default().Record(gofakeit.New(1))
+	assert.NotEmpty(t, record.FirstName)
+	assert.Equal(t, "Doe", record.LastName)
+
+	sparse, err := registry.Get("sparse")
+	require.NoError(t, err)
+	assert.Empty(t, sparse.Record(gofakeit.New(1)).FirstName, "file profiles replace embedded ones")
+
+	names := make([]string, 0)
+	for _, profile := range registry.Profiles() {
+		names = append(names, profile.Name())
+	}
+	assert.Equal(t, []string{"classic", "full", "names", "sparse"}, names)
+}
+
+func TestLoadRegistryRejectsBadProfiles(t *testing.T) {
+	t.Parallel()
+	_, err := LoadRegistry("", "missing")
+	assert.ErrorIs(t, err, ErrUnknownProfile)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "typo.yaml"), []byte(`
+name: typo
+fields:
+  - {field: firstName, fnuc: firstname}
+`), 0o644))
+	_, err = LoadRegistry(dir, DefaultProfile)
+	assert.ErrorContains(t, err, "typo.yaml")
+}
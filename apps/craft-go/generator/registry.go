@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultProfile is the profile used when a request names none.
+const DefaultProfile = "full"
+
+// ErrUnknownProfile is returned when a profile name is not registered.
+var ErrUnknownProfile = errors.New("unknown generator profile")
+
+//go:embed profiles/*.yaml
+var builtinFiles embed.FS
+
+// Registry holds the profiles available to ?profile= requests.
+type Registry struct {
+	profiles    map[string]*Profile
+	defaultName string
+}
+
+var builtin = func() *Registry {
+	registry := &Registry{profiles: make(map[string]*Profile), defaultName: DefaultProfile}
+	if err := registry.loadFS(builtinFiles, "profiles"); err != nil {
+		panic(err)
+	}
+	return registry
+}()
+
+// Builtin returns the registry of embedded profiles: full, sparse and
+// classic, with full as the default.
+func Builtin() *Registry {
+	return builtin
+}
+
+// LoadRegistry returns the embedded profiles plus every .yaml, .yml and .json
+// profile in dir, which may be empty to load none. A file profile replaces
+// an embedded profile of the same name. defaultName must name a loaded
+// profile.
+func LoadRegistry(dir, defaultName string) (*Registry, error) {
+	registry := &Registry{profiles: make(map[string]*Profile, len(builtin.profiles)), defaultName: defaultName}
+	for name, profile := range builtin.profiles {
+		registry.profiles[name] = profile
+	}
+	if dir != "" {
+		if err := registry.loadFS(os.DirFS(dir), "."); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := registry.profiles[defaultName]; !ok {
+		return nil, fmt.Errorf("%w: default %q", ErrUnknownProfile, defaultName)
+	}
+	return registry, nil
+}
+
+func (r *Registry) loadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("read profiles: %w", err)
+	}
+	for _, entry := range entries {
+		name := path.Join(dir, entry.Name())
+		if entry.IsDir() {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("read profile: %w", err)
+		}
+		var spec Spec
+		switch filepath.Ext(name) {
+		case ".yaml", ".yml":
+			decoder := yaml.NewDecoder(bytes.NewReader(data))
+			decoder.KnownFields(true)
+			err = decoder.Decode(&spec)
+		case ".json":
+			decoder := json.NewDecoder(bytes.NewReader(data))
+			decoder.DisallowUnknownFields()
+			err = decoder.Decode(&spec)
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("parse profile %s: %w", entry.Name(), err)
+		}
+		profile, err := Compile(spec)
+		if err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		r.profiles[profile.Name()] = profile
+	}
+	return nil
+}
+
+// Get returns the named profile, or the default profile for "".
+func (r *Registry) Get(name string) (*Profile, error) {
+	if name == "" {
+		name = r.defaultName
+	}
+	profile, ok := r.profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownProfile, name)
+	}
+	return profile, nil
+}
+
+// Default returns the profile used when a request names none.
+func (r *Registry) Default() *Profile {
+	return r.profiles[r.defaultName]
+}
+
+// Profiles returns every registered profile ordered by name.
+func (r *Registry) Profiles() []*Profile {
+	profiles := make([]*Profile, 0, len(r.profiles))
+	for _, profile := range r.profiles {
+		profiles = append(profiles, profile)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name() < profiles[j].Name() })
+	return profiles
+}
@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+// seededFuncs replace gofakeit lookups that draw from the global random
+// source, which would make datasets differ between runs with the same seed.
+var seededFuncs = map[string]*gofakeit.Info{
+	"date": {
+		Display:     "Date",
+		Category:    "time",
+		Description: "Random date between 1970 and 2030",
+		Output:      "string",
+		Params: []gofakeit.Param{
+			{Field: "layout", Display: "Layout", Type: "string", Default: time.RFC3339, Description: "Go time layout of the output"},
+		},
+		Generate: func(r *rand.Rand, m *gofakeit.MapParams, info *gofakeit.Info) (any, error) {
+			layout, err := info.GetString(m, "layout")
+			if err != nil {
+				return nil, err
+			}
+			start := time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+			end := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+			return randomTime(r, start, end).Format(layout), nil
+		},
+	},
+	"daterange": {
+		Display:     "DateRange",
+		Category:    "time",
+		Description: "Random date between startdate and enddate",
+		Output:      "string",
+		Params: []gofakeit.Param{
+			{Field: "startdate", Display: "Start Date", Type: "string", Default: "1970-01-01", Description: "Earliest date, in layout"},
+			{Field: "enddate", Display: "End Date", Type: "string", Default: "2025-12-31", Description: "Latest date, in layout"},
+			{Field: "layout", Display: "Layout", Type: "string", Default: time.DateOnly, Description: "Go time layout of the dates"},
+		},
+		Generate: func(r *rand.Rand, m *gofakeit.MapParams, info *gofakeit.Info) (any, error) {
+			layout, err := info.GetString(m, "layout")
+			if err != nil {
+				return nil, err
+			}
+			var bounds [2]time.Time
+			for i, name := range []string{"startdate", "enddate"} {
+				value, err := info.GetString(m, name)
+				if err != nil {
+					return nil, err
+				}
+				if bounds[i], err = time.Parse(layout, value); err != nil {
+					return nil, err
+				}
+			}
+			return randomTime(r, bounds[0], bounds[1]).Format(layout), nil
+		},
+	},
+}
+
+// randomTime returns a time in [start, end], or start when end is earlier,
+// with one-second resolution.
+func randomTime(r *rand.Rand, start, end time.Time) time.Time {
+	seconds := int64(end.Sub(start) / time.Second)
+	if seconds <= 0 {
+		return start
+	}
+	return start.Add(time.Duration(r.Int63n(seconds+1)) * time.Second)
+}
@@ -0,0 +1,142 @@
+package graphqlapi
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// complexity estimates how many values an operation resolves: every selected
+// field counts once, and the selections under a records connection count once
+// per requested record. A sorted connection also ranks every record before
+// its after cursor, so each of those counts once too. Invalid documents must
+// be rejected by validation first; fragment cycles would not terminate.
+func complexity(document *ast.Document, operationName string, variables map[string]any) int {
+	fragments := map[string]*ast.FragmentDefinition{}
+	var operation *ast.OperationDefinition
+	for _, definition := range document.Definitions {
+		switch definition := definition.(type) {
+		case *ast.FragmentDefinition:
+			fragments[definition.Name.Value] = definition
+		case *ast.OperationDefinition:
+			if operationName == "" || (definition.Name != nil && definition.Name.Value == operationName) {
+				operation = definition
+			}
+		}
+	}
+	if operation == nil {
+		return 0
+	}
+
+	defaults := map[string]ast.Value{}
+	for _, definition := range operation.VariableDefinitions {
+		if definition.DefaultValue != nil {
+			defaults[definition.Variable.Name.Value] = definition.DefaultValue
+		}
+	}
+	counter := complexityCounter{fragments: fragments, variables: variables, defaults: defaults}
+	return counter.selectionSet(operation.SelectionSet)
+}
+
+type complexityCounter struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+	defaults  map[string]ast.Value
+}
+
+func (c complexityCounter) selectionSet(set *ast.SelectionSet) int {
+	if set == nil {
+		return 0
+	}
+	total := 0
+	for _, selection := range set.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			total += 1 + c.multiplier(selection)*c.selectionSet(selection.SelectionSet) + c.skipped(selection)
+		case *ast.InlineFragment:
+			total += c.selectionSet(selection.SelectionSet)
+		case *ast.FragmentSpread:
+			if fragment, ok := c.fragments[selection.Name.Value]; ok {
+				total += c.selectionSet(fragment.SelectionSet)
+			}
+		}
+	}
+	return total
+}
+
+// multiplier is the number of records a records connection requests, or 1
+// for any other field.
+func (c complexityCounter) multiplier(field *ast.Field) int {
+	if field.SelectionSet == nil || field.Name.Value != "records" {
+		return 1
+	}
+	for _, argument := range field.Arguments {
+		if argument.Name.Value == "first" {
+			return max(c.intValue(argument.Value), 1)
+		}
+	}
+	return DefaultFirst
+}
+
+// skipped is the number of records a sorted records connection ranks before
+// its after cursor. Unsorted connections skip them without keeping them.
+func (c complexityCounter) skipped(field *ast.Field) int {
+	if field.Name.Value != "records" {
+		return 0
+	}
+	var sort, after string
+	for _, argument := range field.Arguments {
+		switch argument.Name.Value {
+		case "sort":
+			sort = c.stringValue(argument.Value)
+		case "after":
+			after = c.stringValue(argument.Value)
+		}
+	}
+	if strings.TrimSpace(sort) == "" || after == "" {
+		return 0
+	}
+	position, err := decodeCursor(after)
+	if err != nil {
+		// Invalid cursors fail in the resolver before any record is read.
+		return 0
+	}
+	return position + 1
+}
+
+func (c complexityCounter) stringValue(value ast.Value) string {
+	switch value := value.(type) {
+	case *ast.StringValue:
+		return value.Value
+	case *ast.Variable:
+		if s, ok := c.variables[value.Name.Value].(string); ok {
+			return s
+		}
+		if fallback, ok := c.defaults[value.Name.Value]; ok {
+			return c.stringValue(fallback)
+		}
+	}
+	return ""
+}
+
+func (c complexityCounter) intValue(value ast.Value) int {
+	switch value := value.(type) {
+	case *ast.IntValue:
+		n, _ := strconv.Atoi(value.Value)
+		return n
+	case *ast.Variable:
+		switch n := c.variables[value.Name.Value].(type) {
+		case float64:
+			return int(n)
+		case int:
+			return n
+		case nil:
+			if fallback, ok := c.defaults[value.Name.Value]; ok {
+				return c.intValue(fallback)
+			}
+			return DefaultFirst
+		}
+	}
+	return 0
+}
@@ -0,0 +1,274 @@
+// Package graphqlapi serves the record dataset over GraphQL, so clients fetch
+// only the record fields they display. The object types are generated from
+// the models structs and named by their JSON keys, matching the REST API.
+package graphqlapi
+
+import (
+	"craft-fusion/craft-go/models"
+	"craft-fusion/craft-go/services"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// DefaultFirst is the page size of a records connection that sets no first
+// argument.
+const DefaultFirst = 100
+
+// cursorPrefix marks record cursors, which encode the offset of an edge in
+// the filtered and sorted listing.
+const cursorPrefix = "record:"
+
+// NewSchema builds the GraphQL schema over records:
+//
+//	record(UID: String!): Record
+//	records(first, after, sort, state, city, lastName, zipcode, q, minIncome, maxIncome): RecordConnection!
+func NewSchema(records *services.RecordService) (graphql.Schema, error) {
+	recordType := objectType(reflect.TypeOf(models.Record{}), map[reflect.Type]*graphql.Object{})
+	pageInfoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: graphql.Fields{
+			"hasNextPage":     &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"hasPreviousPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+			"startCursor":     &graphql.Field{Type: graphql.String},
+			"endCursor":       &graphql.Field{Type: graphql.String},
+		},
+	})
+	edgeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "RecordEdge",
+		Fields: graphql.Fields{
+			"cursor": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"node":   &graphql.Field{Type: graphql.NewNonNull(recordType)},
+		},
+	})
+	connectionType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "RecordConnection",
+		Description: "A page of records. nodes is a shorthand for edges { node }.",
+		Fields: graphql.Fields{
+			"totalCount": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"edges":      &graphql.Field{Type: nonNullList(edgeType)},
+			"nodes":      &graphql.Field{Type: nonNullList(recordType)},
+			"pageInfo":   &graphql.Field{Type: graphql.NewNonNull(pageInfoType)},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"record": &graphql.Field{
+				Type:        recordType,
+				Description: "The record with the given UID, or null.",
+				Args: graphql.FieldConfigArgument{
+					"UID": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					record, err := records.GetRecordByUID(p.Args["UID"].(string))
+					if err != nil {
+						return nil, nil
+					}
+					return record, nil
+				},
+			},
+			"records": &graphql.Field{
+				Type:        graphql.NewNonNull(connectionType),
+				Description: "Records matching the filters, in sort order. Filters and sort take the same values as GET /api-go/records.",
+				Args: graphql.FieldConfigArgument{
+					"first":     &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: DefaultFirst, Description: "Page size"},
+					"after":     &graphql.ArgumentConfig{Type: graphql.String, Description: "Cursor of the edge to continue after"},
+					"sort":      &graphql.ArgumentConfig{Type: graphql.String, Description: `Comma-separated fields, "-" for descending, such as "lastName,-totalHouseholdIncome"`},
+					"state":     &graphql.ArgumentConfig{Type: graphql.String},
+					"city":      &graphql.ArgumentConfig{Type: graphql.String},
+					"lastName":  &graphql.ArgumentConfig{Type: graphql.String},
+					"zipcode":   &graphql.ArgumentConfig{Type: graphql.String},
+					"q":         &graphql.ArgumentConfig{Type: graphql.String, Description: "Case-insensitive substring of names, address or email"},
+					"minIncome": &graphql.ArgumentConfig{Type: graphql.Float},
+					"maxIncome": &graphql.ArgumentConfig{Type: graphql.Float},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return resolveRecords(records, p.Args)
+				},
+			},
+		},
+	})
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+// connection is the resolved value of a RecordConnection.
+type connection struct {
+	TotalCount int             `json:"totalCount"`
+	Edges      []edge          `json:"edges"`
+	Nodes      []models.Record `json:"nodes"`
+	PageInfo   pageInfo        `json:"pageInfo"`
+}
+
+type edge struct {
+	Cursor string        `json:"cursor"`
+	Node   models.Record `json:"node"`
+}
+
+type pageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor"`
+	EndCursor       *string `json:"endCursor"`
+}
+
+func resolveRecords(records *services.RecordService, args map[string]any) (connection, error) {
+	first, _ := args["first"].(int)
+	if first < 0 {
+		return connection{}, fmt.Errorf("first must not be negative")
+	}
+	offset := 0
+	if after, ok := args["after"].(string); ok {
+		position, err := decodeCursor(after)
+		if err != nil {
+			return connection{}, err
+		}
+		offset = position + 1
+	}
+	sort, err := services.ParseSort(stringArg(args, "sort"))
+	if err != nil {
+		return connection{}, err
+	}
+
+	query := services.RecordQuery{
+		Limit:     first,
+		Offset:    offset,
+		Sort:      sort,
+		State:     stringArg(args, "state"),
+		City:      stringArg(args, "city"),
+		LastName:  stringArg(args, "lastName"),
+		Zipcode:   stringArg(args, "zipcode"),
+		Q:         stringArg(args, "q"),
+		MinIncome: floatArg(args, "minIncome"),
+		MaxIncome: floatArg(args, "maxIncome"),
+	}
+	if first == 0 {
+		// A Limit of 0 means no limit; fetch one record to learn the total.
+		query.Limit, query.Offset = 1, 0
+	}
+	page, err := records.QueryRecords(query)
+	if err != nil {
+		return connection{}, err
+	}
+
+	result := connection{TotalCount: page.Total, Edges: []edge{}, Nodes: []models.Record{}}
+	if first > 0 && len(page.Records) > 0 {
+		result.Nodes = page.Records
+		result.Edges = make([]edge, len(result.Nodes))
+		for i, record := range result.Nodes {
+			result.Edges[i] = edge{Cursor: encodeCursor(offset + i), Node: record}
+		}
+		result.PageInfo.StartCursor = &result.Edges[0].Cursor
+		result.PageInfo.EndCursor = &result.Edges[len(result.Edges)-1].Cursor
+	}
+	result.PageInfo.HasPreviousPage = offset > 0
+	result.PageInfo.HasNextPage = offset+len(result.Edges) < page.Total
+	return result, nil
+}
+
+func encodeCursor(offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.StdEncoding.DecodeString(cursor)
+	if err == nil && strings.HasPrefix(string(raw), cursorPrefix) {
+		if offset, err := strconv.Atoi(strings.TrimPrefix(string(raw), cursorPrefix)); err == nil && offset >= 0 {
+			return offset, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid cursor %q", cursor)
+}
+
+func stringArg(args map[string]any, name string) string {
+	value, _ := args[name].(string)
+	return value
+}
+
+func floatArg(args map[string]any, name string) *float64 {
+	if value, ok := args[name].(float64); ok {
+		return &value
+	}
+	return nil
+}
+
+// objectType generates an object type for a models struct. Fields are named
+// by their JSON keys; pointers are nullable and every other field is
+// non-null. Fields without a GraphQL equivalent, such as the untyped avatar,
+// are left out.
+func objectType(structType reflect.Type, types map[reflect.Type]*graphql.Object) *graphql.Object {
+	if object, ok := types[structType]; ok {
+		return object
+	}
+	fields := graphql.Fields{}
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		fieldType := outputType(field.Type, types)
+		if fieldType == nil || !field.IsExported() {
+			continue
+		}
+		fields[services.JSONFieldName(field)] = &graphql.Field{Type: fieldType, Resolve: fieldResolver(field.Index)}
+	}
+	object := graphql.NewObject(graphql.ObjectConfig{Name: structType.Name(), Fields: fields})
+	types[structType] = object
+	return object
+}
+
+func outputType(goType reflect.Type, types map[reflect.Type]*graphql.Object) graphql.Output {
+	if goType.Kind() == reflect.Pointer {
+		// Strip the non-null wrapper added for the element.
+		if nonNull, ok := outputType(goType.Elem(), types).(*graphql.NonNull); ok {
+			return nonNull.OfType
+		}
+		return nil
+	}
+	var elem graphql.Output
+	switch goType.Kind() {
+	case reflect.String:
+		elem = graphql.String
+	case reflect.Bool:
+		elem = graphql.Boolean
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		elem = graphql.Int
+	case reflect.Float32, reflect.Float64:
+		elem = graphql.Float
+	case reflect.Struct:
+		elem = objectType(goType, types)
+	case reflect.Slice:
+		item := outputType(goType.Elem(), types)
+		if item == nil {
+			return nil
+		}
+		elem = graphql.NewList(item)
+	default:
+		return nil
+	}
+	return graphql.NewNonNull(elem)
+}
+
+// fieldResolver reads the struct field at index from a models value.
+func fieldResolver(index []int) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		value := reflect.Indirect(reflect.ValueOf(p.Source)).FieldByIndex(index)
+		if value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				return nil, nil
+			}
+			value = value.Elem()
+		}
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			// Lists are non-null; an unset slice is an empty list.
+			return []any{}, nil
+		}
+		return value.Interface(), nil
+	}
+}
+
+func nonNullList(item graphql.Type) graphql.Output {
+	return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(item)))
+}
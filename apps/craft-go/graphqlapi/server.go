@@ -0,0 +1,61 @@
+package graphqlapi
+
+import (
+	"context"
+	"craft-fusion/craft-go/services"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// DefaultMaxComplexity admits a page of about 5,000 records with a dozen
+// fields each, and rejects listings of the whole dataset.
+const DefaultMaxComplexity = 100000
+
+// Request is a GraphQL request as sent over HTTP.
+type Request struct {
+	Query         string         `json:"query" form:"query" example:"{ records(first: 25, state: \"Colorado\") { totalCount nodes { UID firstName lastName } } }"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	OperationName string         `json:"operationName,omitempty" form:"operationName"`
+}
+
+// Server executes GraphQL requests against the record dataset.
+type Server struct {
+	schema        graphql.Schema
+	maxComplexity int
+}
+
+// New returns a Server over records that rejects operations whose complexity
+// exceeds maxComplexity; see DefaultMaxComplexity.
+func New(records *services.RecordService, maxComplexity int) (*Server, error) {
+	schema, err := NewSchema(records)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{schema: schema, maxComplexity: maxComplexity}, nil
+}
+
+// Execute parses, validates and runs request. Operations over the complexity
+// limit fail before any record is read.
+func (s *Server) Execute(ctx context.Context, request Request) *graphql.Result {
+	document, err := parser.Parse(parser.ParseParams{Source: request.Query})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+	if validation := graphql.ValidateDocument(&s.schema, document, nil); !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}
+	}
+	if cost := complexity(document, request.OperationName, request.Variables); cost > s.maxComplexity {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(fmt.Errorf(
+			"query complexity %d exceeds the limit of %d; request fewer records or fields", cost, s.maxComplexity))}
+	}
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           document,
+		OperationName: request.OperationName,
+		Args:          request.Variables,
+		Context:       ctx,
+	})
+}
@@ -0,0 +1,178 @@
+package graphqlapi
+
+import (
+	"context"
+	"craft-fusion/craft-go/repository"
+	"craft-fusion/craft-go/services"
+	"encoding/json"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, seedCount, maxComplexity int) (*Server, *services.RecordService) {
+	t.Helper()
+	records := services.NewRecordService(repository.NewMemoryStore())
+	_, err := records.RegenerateRecords(seedCount, 11, "", "")
+	require.NoError(t, err)
+	server, err := New(records, maxComplexity)
+	require.NoError(t, err)
+	return server, records
+}
+
+// execute runs query and decodes its data into out.
+func execute(t *testing.T, server *Server, request Request, out any) {
+	t.Helper()
+	result := server.Execute(context.Background(), request)
+	require.Empty(t, result.Errors)
+	raw, err := json.Marshal(result.Data)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, out))
+}
+
+func errorMessage(t *testing.T, result *graphql.Result) string {
+	t.Helper()
+	require.NotEmpty(t, result.Errors)
+	return result.Errors[0].Message
+}
+
+func TestRecordsConnectionSelectsFieldsAndPages(t *testing.T) {
+	t.Parallel()
+	server, _ := newTestServer(t, 30, DefaultMaxComplexity)
+	const query = `query Page($after: String) {
+		records(first: 20, after: $after, sort: "lastName") {
+			totalCount
+			edges { cursor node { UID lastName address { state } salary { companyName } } }
+			pageInfo { hasNextPage hasPreviousPage endCursor }
+		}
+	}`
+	type page struct {
+		Records struct {
+			TotalCount int
+			Edges      []struct {
+				Cursor string
+				Node   map[string]any
+			}
+			PageInfo struct {
+				HasNextPage     bool
+				HasPreviousPage bool
+				EndCursor       string
+			}
+		}
+	}
+
+	var first page
+	execute(t, server, Request{Query: query}, &first)
+	assert.Equal(t, 30, first.Records.TotalCount)
+	require.Len(t, first.Records.Edges, 20)
+	assert.True(t, first.Records.PageInfo.HasNextPage)
+	assert.False(t, first.Records.PageInfo.HasPreviousPage)
+	node := first.Records.Edges[0].Node
+	assert.ElementsMatch(t, []string{"UID", "lastName", "address", "salary"}, keys(node), "only selected fields are returned")
+	assert.ElementsMatch(t, []string{"state"}, keys(node["address"].(map[string]any)))
+
+	var second page
+	execute(t, server, Request{Query: query, Variables: map[string]any{"after": first.Records.PageInfo.EndCursor}}, &second)
+	require.Len(t, second.Records.Edges, 10)
+	assert.False(t, second.Records.PageInfo.HasNextPage)
+	assert.True(t, second.Records.PageInfo.HasPreviousPage)
+	assert.LessOrEqual(t, first.Records.Edges[19].Node["lastName"], second.Records.Edges[0].Node["lastName"])
+
+	result := server.Execute(context.Background(), Request{Query: `{ records(after: "bogus") { totalCount } }`})
+	assert.Contains(t, errorMessage(t, result), "invalid cursor")
+	result = server.Execute(context.Background(), Request{Query: `{ records(sort: "avatar") { totalCount } }`})
+	assert.Contains(t, errorMessage(t, result), "cannot sort by")
+}
+
+func TestRecordsFiltersAndRecordLookup(t *testing.T) {
+	t.Parallel()
+	server, records := newTestServer(t, 40, DefaultMaxComplexity)
+	page, err := records.QueryRecords(services.RecordQuery{Limit: 1})
+	require.NoError(t, err)
+	target := page.Records[0]
+
+	var filtered struct {
+		Count   struct{ TotalCount int }
+		Records struct {
+			TotalCount int
+			Nodes      []struct{ Address struct{ State string } }
+		}
+	}
+	execute(t, server, Request{
+		Query:     `query($state: String) { count: records(state: $state, first: 0) { totalCount } records(state: $state) { totalCount nodes { address { state } } } }`,
+		Variables: map[string]any{"state": target.Address.State},
+	}, &filtered)
+	require.NotEmpty(t, filtered.Records.Nodes)
+	assert.Equal(t, filtered.Records.TotalCount, filtered.Count.TotalCount)
+	assert.Len(t, filtered.Records.Nodes, filtered.Count.TotalCount)
+	for _, node := range filtered.Records.Nodes {
+		assert.Equal(t, target.Address.State, node.Address.State)
+	}
+
+	var lookup struct {
+		Record  *struct{ UID, Email string }
+		Missing *struct{ UID string }
+	}
+	execute(t, server, Request{
+		Query: `{ record(UID: "` + target.UID + `") { UID email } missing: record(UID: "nope") { UID } }`,
+	}, &lookup)
+	require.NotNil(t, lookup.Record)
+	assert.Equal(t, target.Email, lookup.Record.Email)
+	assert.Nil(t, lookup.Missing)
+}
+
+func TestComplexityLimitRejectsLargeSelections(t *testing.T) {
+	t.Parallel()
+	server, _ := newTestServer(t, 5, 1000)
+
+	var small struct {
+		Records struct{ Nodes []map[string]any }
+	}
+	execute(t, server, Request{Query: `{ records(first: 100) { nodes { UID firstName lastName email } } }`}, &small)
+	assert.Len(t, small.Records.Nodes, 5)
+
+	result := server.Execute(context.Background(), Request{Query: `{ records(first: 1000000) { nodes { UID } } }`})
+	assert.Contains(t, errorMessage(t, result), "exceeds the limit of 1000")
+	assert.Nil(t, result.Data, "rejected operations are not executed")
+
+	result = server.Execute(context.Background(), Request{
+		Query: `{ records(first: 1, sort: "lastName", after: "` + encodeCursor(5000) + `") { nodes { UID } } }`,
+	})
+	assert.Contains(t, errorMessage(t, result), "exceeds the limit of 1000", "deep sorted cursors are charged")
+
+	result = server.Execute(context.Background(), Request{
+		Query:     `query($n: Int) { records(first: $n) { ...row } } fragment row on RecordConnection { nodes { UID firstName } }`,
+		Variables: map[string]any{"n": float64(500)},
+	})
+	assert.Contains(t, errorMessage(t, result), "query complexity 1501 exceeds", "variables and fragments are counted")
+}
+
+func TestComplexityCountsDefaults(t *testing.T) {
+	t.Parallel()
+	count := func(query string, variables map[string]any) int {
+		document, err := parser.Parse(parser.ParseParams{Source: query})
+		require.NoError(t, err)
+		return complexity(document, "", variables)
+	}
+	assert.Equal(t, 1+DefaultFirst*2, count(`{ records { nodes { UID } } }`, nil))
+	assert.Equal(t, 1+7*2, count(`query($n: Int = 7) { records(first: $n) { nodes { UID } } }`, nil))
+	assert.Equal(t, 1+3*4, count(`{ records(first: 3) { edges { cursor node { UID } } } }`, nil))
+	assert.Equal(t, 4, count(`{ record(UID: "x") { UID address { state } } }`, nil))
+
+	deep := encodeCursor(899999)
+	assert.Equal(t, 1+1*2, count(`{ records(first: 1, after: "`+deep+`") { nodes { UID } } }`, nil),
+		"unsorted connections skip records without keeping them")
+	assert.Equal(t, 1+1*2+900000, count(`query($after: String) { records(first: 1, after: $after, sort: "lastName") { nodes { UID } } }`,
+		map[string]any{"after": deep}), "sorted connections rank every record before the cursor")
+}
+
+func keys(m map[string]any) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	return names
+}
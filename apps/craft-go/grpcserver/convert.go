@@ -0,0 +1,110 @@
+package grpcserver
+
+import (
+	"craft-fusion/craft-go/models"
+	recordsv1 "craft-fusion/craft-go/proto/records/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toProtoRecord(record models.Record) *recordsv1.Record {
+	salary := make([]*recordsv1.Company, len(record.Salary))
+	for i, company := range record.Salary {
+		salary[i] = &recordsv1.Company{
+			Uid:             company.UID,
+			EmployeeName:    company.EmployeeName,
+			AnnualSalary:    company.AnnualSalary,
+			CompanyName:     company.CompanyName,
+			CompanyPosition: company.CompanyPosition,
+			Currency:        company.Currency,
+		}
+	}
+	return &recordsv1.Record{
+		Uid:       record.UID,
+		Name:      record.Name,
+		FirstName: record.FirstName,
+		LastName:  record.LastName,
+		Address: &recordsv1.Address{
+			Street:  record.Address.Street,
+			City:    record.Address.City,
+			State:   record.Address.State,
+			Zipcode: record.Address.Zipcode,
+		},
+		City:  record.City,
+		State: record.State,
+		Zip:   record.Zip,
+		Phone: &recordsv1.Phone{
+			Uid:          record.Phone.UID,
+			Number:       record.Phone.Number,
+			Type:         record.Phone.Type,
+			CountryCode:  record.Phone.CountryCode,
+			AreaCode:     record.Phone.AreaCode,
+			Extension:    record.Phone.Extension,
+			HasExtension: record.Phone.HasExtension,
+		},
+		Salary:               salary,
+		Email:                record.Email,
+		BirthDate:            record.BirthDate,
+		TotalHouseholdIncome: record.TotalHouseholdIncome,
+		RegistrationDate:     record.RegistrationDate,
+	}
+}
+
+func fromProtoRecord(record *recordsv1.Record) models.Record {
+	salary := make([]models.Company, len(record.GetSalary()))
+	for i, company := range record.GetSalary() {
+		salary[i] = models.Company{
+			UID:             company.GetUid(),
+			EmployeeName:    company.GetEmployeeName(),
+			AnnualSalary:    company.GetAnnualSalary(),
+			CompanyName:     company.GetCompanyName(),
+			CompanyPosition: company.CompanyPosition,
+			Currency:        company.Currency,
+		}
+	}
+	address, phone := record.GetAddress(), record.GetPhone()
+	if phone == nil {
+		// The optional fields are read directly to keep them unset.
+		phone = &recordsv1.Phone{}
+	}
+	return models.Record{
+		UID:       record.GetUid(),
+		Name:      record.GetName(),
+		FirstName: record.GetFirstName(),
+		LastName:  record.GetLastName(),
+		Address: models.Address{
+			Street:  address.GetStreet(),
+			City:    address.GetCity(),
+			State:   address.GetState(),
+			Zipcode: address.GetZipcode(),
+		},
+		City:  record.GetCity(),
+		State: record.GetState(),
+		Zip:   record.GetZip(),
+		Phone: models.Phone{
+			UID:          phone.GetUid(),
+			Number:       phone.GetNumber(),
+			Type:         phone.GetType(),
+			CountryCode:  phone.CountryCode,
+			AreaCode:     phone.AreaCode,
+			Extension:    phone.Extension,
+			HasExtension: phone.HasExtension,
+		},
+		Salary:               salary,
+		Email:                record.GetEmail(),
+		BirthDate:            record.GetBirthDate(),
+		TotalHouseholdIncome: record.GetTotalHouseholdIncome(),
+		RegistrationDate:     record.GetRegistrationDate(),
+	}
+}
+
+func toProtoRun(run models.GenerationRun) *recordsv1.GenerationRun {
+	return &recordsv1.GenerationRun{
+		Source:           run.Source,
+		Count:            int32(run.Count),
+		DurationMs:       run.DurationMs,
+		RecordsPerSecond: run.RecordsPerSecond,
+		Seed:             run.Seed,
+		Timestamp:        timestamppb.New(run.Timestamp),
+	}
+}
@@ -0,0 +1,179 @@
+// Package grpcserver serves the record API of proto/records/v1 over gRPC,
+// backed by the same RecordService as the HTTP handlers.
+package grpcserver
+
+import (
+	"context"
+	"craft-fusion/craft-go/models"
+	recordsv1 "craft-fusion/craft-go/proto/records/v1"
+	"craft-fusion/craft-go/repository"
+	"craft-fusion/craft-go/services"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultListLimit matches the default limit of GET /api-go/records.
+	defaultListLimit = 1000
+	// maxListLimit matches the largest listing the HTTP API returns.
+	maxListLimit = 1000000
+)
+
+// New returns a gRPC server exposing records with server reflection enabled,
+// so tools such as grpcurl can discover the API.
+func New(records *services.RecordService, options ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(options...)
+	recordsv1.RegisterRecordServiceServer(server, NewRecordServer(records))
+	reflection.Register(server)
+	return server
+}
+
+// RecordServer implements recordsv1.RecordServiceServer.
+type RecordServer struct {
+	recordsv1.UnimplementedRecordServiceServer
+	records *services.RecordService
+}
+
+// NewRecordServer returns a RecordServer backed by records.
+func NewRecordServer(records *services.RecordService) *RecordServer {
+	return &RecordServer{records: records}
+}
+
+// ListRecords returns one page of matching records.
+func (s *RecordServer) ListRecords(_ context.Context, request *recordsv1.ListRecordsRequest) (*recordsv1.ListRecordsResponse, error) {
+	query, err := recordQuery(request, defaultListLimit)
+	if err != nil {
+		return nil, err
+	}
+	page, err := s.records.QueryRecords(query)
+	if err != nil {
+		return nil, recordError(err)
+	}
+	response := &recordsv1.ListRecordsResponse{
+		Records:  make([]*recordsv1.Record, len(page.Records)),
+		Total:    int32(page.Total),
+		Page:     int32(page.Page),
+		PageSize: int32(page.PageSize),
+	}
+	for i, record := range page.Records {
+		response.Records[i] = toProtoRecord(record)
+	}
+	return response, nil
+}
+
+// StreamRecords sends each matching record as a separate message as it is
+// read from the store, so neither side holds the whole listing. Sorted
+// listings still gather their window of matches before the first send.
+func (s *RecordServer) StreamRecords(request *recordsv1.ListRecordsRequest, stream grpc.ServerStreamingServer[recordsv1.Record]) error {
+	query, err := recordQuery(request, 0)
+	if err != nil {
+		return err
+	}
+	sent := false
+	_, err = s.records.EachRecord(query, func(record models.Record) error {
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		sent = true
+		return stream.Send(toProtoRecord(record))
+	})
+	if err != nil && !sent {
+		return recordError(err)
+	}
+	return err
+}
+
+// GetRecord returns the record with the requested UID.
+func (s *RecordServer) GetRecord(_ context.Context, request *recordsv1.GetRecordRequest) (*recordsv1.Record, error) {
+	record, err := s.records.GetRecordByUID(request.GetUid())
+	if err != nil {
+		return nil, recordError(err)
+	}
+	return toProtoRecord(record), nil
+}
+
+// CreateRecord validates and stores a new record.
+func (s *RecordServer) CreateRecord(_ context.Context, request *recordsv1.CreateRecordRequest) (*recordsv1.Record, error) {
+	if request.GetRecord() == nil {
+		return nil, status.Error(codes.InvalidArgument, "record is required")
+	}
+	record := fromProtoRecord(request.GetRecord())
+	if err := services.ValidateRecord(record); err != nil {
+		return nil, recordError(err)
+	}
+	created, err := s.records.CreateRecord(record)
+	if err != nil {
+		return nil, recordError(err)
+	}
+	return toProtoRecord(created), nil
+}
+
+// GetGenerationStats returns the generation history, oldest run first.
+func (s *RecordServer) GetGenerationStats(context.Context, *recordsv1.GetGenerationStatsRequest) (*recordsv1.GetGenerationStatsResponse, error) {
+	runs := s.records.Stats().History()
+	response := &recordsv1.GetGenerationStatsResponse{Runs: make([]*recordsv1.GenerationRun, len(runs))}
+	for i, run := range runs {
+		response.Runs[i] = toProtoRun(run)
+	}
+	return response, nil
+}
+
+// recordQuery converts a listing request, applying defaultLimit when the
+// request sets none (0 for no limit).
+func recordQuery(request *recordsv1.ListRecordsRequest, defaultLimit int) (services.RecordQuery, error) {
+	limit := int(request.GetLimit())
+	if limit < 0 || limit > maxListLimit {
+		return services.RecordQuery{}, status.Error(codes.InvalidArgument, "limit must be between 0 and 1,000,000")
+	}
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	sort, err := services.ParseSort(request.GetSort())
+	if err != nil {
+		return services.RecordQuery{}, recordError(err)
+	}
+	return services.RecordQuery{
+		Page:      int(request.GetPage()),
+		PageSize:  int(request.GetPageSize()),
+		Limit:     limit,
+		Sort:      sort,
+		State:     request.GetState(),
+		City:      request.GetCity(),
+		LastName:  request.GetLastName(),
+		Zipcode:   request.GetZipcode(),
+		MinIncome: request.MinIncome,
+		MaxIncome: request.MaxIncome,
+		Q:         request.GetQ(),
+	}, nil
+}
+
+// recordError maps domain errors to gRPC status codes the way the HTTP
+// problem middleware maps them to statuses.
+func recordError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	kind, _ := repository.KindOf(err)
+	code, ok := errorCodes[kind]
+	switch {
+	case errors.Is(err, services.ErrJobFinished):
+		// The job exists but is no longer in a state that can be canceled.
+		code = codes.FailedPrecondition
+	case !ok:
+		return status.Error(codes.Internal, "record operation failed")
+	}
+	return status.Error(code, err.Error())
+}
+
+// errorCodes maps each domain error kind to its gRPC status code.
+var errorCodes = map[repository.Kind]codes.Code{
+	repository.KindNotFound:      codes.NotFound,
+	repository.KindValidation:    codes.InvalidArgument,
+	repository.KindConflict:      codes.AlreadyExists,
+	repository.KindLimitExceeded: codes.InvalidArgument,
+	repository.KindUnavailable:   codes.Unavailable,
+}
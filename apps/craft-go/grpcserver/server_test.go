@@ -0,0 +1,171 @@
+package grpcserver
+
+import (
+	"context"
+	recordsv1 "craft-fusion/craft-go/proto/records/v1"
+	"craft-fusion/craft-go/repository"
+	"craft-fusion/craft-go/services"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// newTestClient serves a service seeded with seedCount records over an
+// in-memory connection.
+func newTestClient(t *testing.T, seedCount int) (*grpc.ClientConn, *services.RecordService) {
+	t.Helper()
+	records := services.NewRecordService(repository.NewMemoryStore())
+	_, err := records.RegenerateRecords(seedCount, 7, "", "")
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1 << 20)
+	server := New(records)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn, records
+}
+
+func TestListRecordsPagesAndFilters(t *testing.T) {
+	t.Parallel()
+	conn, records := newTestClient(t, 50)
+	client := recordsv1.NewRecordServiceClient(conn)
+	ctx := context.Background()
+
+	page, err := client.ListRecords(ctx, &recordsv1.ListRecordsRequest{Page: 2, PageSize: 10, Sort: "lastName"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(50), page.Total)
+	assert.Equal(t, int32(2), page.Page)
+	require.Len(t, page.Records, 10)
+	assert.LessOrEqual(t, page.Records[0].LastName, page.Records[9].LastName)
+
+	first, err := records.GetRecordByUID(page.Records[0].Uid)
+	require.NoError(t, err)
+	assert.Equal(t, first.Address.State, page.Records[0].Address.State)
+	filtered, err := client.ListRecords(ctx, &recordsv1.ListRecordsRequest{State: first.Address.State, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, filtered.Records, 1)
+	assert.Equal(t, first.Address.State, filtered.Records[0].Address.State)
+
+	_, err = client.ListRecords(ctx, &recordsv1.ListRecordsRequest{Sort: "avatar"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.ListRecords(ctx, &recordsv1.ListRecordsRequest{Limit: 1000001})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestStreamRecordsSendsEveryMatch(t *testing.T) {
+	t.Parallel()
+	conn, _ := newTestClient(t, 2500)
+	client := recordsv1.NewRecordServiceClient(conn)
+
+	stream, err := client.StreamRecords(context.Background(), &recordsv1.ListRecordsRequest{})
+	require.NoError(t, err)
+	uids := map[string]bool{}
+	for {
+		record, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		uids[record.Uid] = true
+	}
+	assert.Len(t, uids, 2500, "streaming is not capped at the default listing limit")
+
+	stream, err = client.StreamRecords(context.Background(), &recordsv1.ListRecordsRequest{Sort: "password"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRecordErrorMapsKinds(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, codes.FailedPrecondition, status.Code(recordError(services.ErrJobFinished)))
+	assert.Equal(t, codes.AlreadyExists, status.Code(recordError(services.ErrRecordExists)))
+	assert.Equal(t, codes.Unavailable, status.Code(recordError(services.ErrTooManyJobs)))
+	assert.Equal(t, codes.InvalidArgument, status.Code(recordError(services.ErrPageTooLarge)))
+	assert.Equal(t, codes.Internal, status.Code(recordError(repository.NewError("unknown", "unknown kind"))))
+	assert.Equal(t, codes.Internal, status.Code(recordError(errors.New("disk full"))))
+}
+
+func TestGetAndCreateRecord(t *testing.T) {
+	t.Parallel()
+	conn, _ := newTestClient(t, 1)
+	client := recordsv1.NewRecordServiceClient(conn)
+	ctx := context.Background()
+
+	_, err := client.GetRecord(ctx, &recordsv1.GetRecordRequest{Uid: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	position := "Engineer"
+	created, err := client.CreateRecord(ctx, &recordsv1.CreateRecordRequest{Record: &recordsv1.Record{
+		FirstName: "Ada",
+		LastName:  "Lovelace",
+		Address:   &recordsv1.Address{State: "Colorado"},
+		Salary:    []*recordsv1.Company{{CompanyName: "Analytical", AnnualSalary: 1000, CompanyPosition: &position}},
+	}})
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.Uid)
+
+	fetched, err := client.GetRecord(ctx, &recordsv1.GetRecordRequest{Uid: created.Uid})
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(created, fetched))
+	assert.Equal(t, "Engineer", fetched.Salary[0].GetCompanyPosition())
+
+	_, err = client.CreateRecord(ctx, &recordsv1.CreateRecordRequest{Record: created})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	_, err = client.CreateRecord(ctx, &recordsv1.CreateRecordRequest{Record: &recordsv1.Record{LastName: "Nameless"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "firstName is required")
+	_, err = client.CreateRecord(ctx, &recordsv1.CreateRecordRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetGenerationStats(t *testing.T) {
+	t.Parallel()
+	conn, _ := newTestClient(t, 3)
+	client := recordsv1.NewRecordServiceClient(conn)
+
+	stats, err := client.GetGenerationStats(context.Background(), &recordsv1.GetGenerationStatsRequest{})
+	require.NoError(t, err)
+	require.Len(t, stats.Runs, 1)
+	assert.Equal(t, services.SourceSeed, stats.Runs[0].Source)
+	assert.Equal(t, int32(3), stats.Runs[0].Count)
+	assert.Equal(t, int64(7), stats.Runs[0].Seed)
+	assert.False(t, stats.Runs[0].Timestamp.AsTime().IsZero())
+}
+
+func TestReflectionListsRecordService(t *testing.T) {
+	t.Parallel()
+	conn, _ := newTestClient(t, 0)
+	stream, err := reflectionv1.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_ListServices{},
+	}))
+	response, err := stream.Recv()
+	require.NoError(t, err)
+
+	var names []string
+	for _, service := range response.GetListServicesResponse().GetService() {
+		names = append(names, service.GetName())
+	}
+	assert.Contains(t, names, "craftgo.records.v1.RecordService")
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"craft-fusion/craft-go/models"
+	"craft-fusion/craft-go/repository"
+	"craft-fusion/craft-go/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// contractExpectation is a documented response. Body is matched as a JSON
+// subset, Keys only requires the listed top-level fields and Length checks
+// the size of an array body.
+type contractExpectation struct {
+	Status int      `json:"status"`
+	Body   any      `json:"body"`
+	Keys   []string `json:"keys"`
+	Length *int     `json:"length"`
+}
+
+type contractCase struct {
+	Name       string               `json:"name"`
+	Method     string               `json:"method"`
+	Paths      []string             `json:"paths"`
+	Nest       contractExpectation  `json:"nest"`
+	Go         *contractExpectation `json:"go"`
+	Divergence string               `json:"divergence"`
+}
+
+type contractFixture struct {
+	Dataset []models.Record `json:"dataset"`
+	Cases   []contractCase  `json:"cases"`
+}
+
+// TestNestRecordsContract replays the NestJS record fixtures against the
+// craft-go routes so parity drift shows up as a failing case. The NestJS
+// side replays the same file in records.contract.e2e.spec.ts.
+func TestNestRecordsContract(t *testing.T) {
+	t.Parallel()
+	raw, err := os.ReadFile("testdata/nest_records_contract.json")
+	require.NoError(t, err)
+	var fixture contractFixture
+	require.NoError(t, json.Unmarshal(raw, &fixture))
+
+	store := repository.NewMemoryStore()
+	require.NoError(t, store.Replace(fixture.Dataset))
+	router := gin.New()
+	RegisterRecordRoutes(router, NewRecordHandler(services.NewRecordService(store)))
+
+	for _, contract := range fixture.Cases {
+		expected := contract.Nest
+		if contract.Go != nil {
+			require.NotEmpty(t, contract.Divergence, "%s: a go expectation needs a documented divergence", contract.Name)
+			expected = *contract.Go
+		}
+		method := contract.Method
+		if method == "" {
+			method = http.MethodGet
+		}
+
+		for _, path := range contract.Paths {
+			t.Run(contract.Name+" "+path, func(t *testing.T) {
+				response := httptest.NewRecorder()
+				router.ServeHTTP(response, httptest.NewRequest(method, path, nil))
+				require.Equal(t, expected.Status, response.Code, response.Body.String())
+
+				var body any
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+				if expected.Body != nil {
+					assertJSONSubset(t, expected.Body, body, "$")
+				}
+				for _, key := range expected.Keys {
+					assert.Contains(t, body, key)
+				}
+				if expected.Length != nil {
+					assert.Len(t, body, *expected.Length)
+				}
+			})
+		}
+	}
+}
+
+// assertJSONSubset checks that every field of expected is present in actual
+// with an equal value; actual may carry extra object fields.
+func assertJSONSubset(t *testing.T, expected, actual any, path string) {
+	t.Helper()
+	switch want := expected.(type) {
+	case map[string]any:
+		got, ok := actual.(map[string]any)
+		if !assert.True(t, ok, "%s: expected an object, got %v", path, actual) {
+			return
+		}
+		for key, value := range want {
+			if assert.Contains(t, got, key, "%s: missing field", path) {
+				assertJSONSubset(t, value, got[key], path+"."+key)
+			}
+		}
+	case []any:
+		got, ok := actual.([]any)
+		if !assert.True(t, ok, "%s: expected an array, got %v", path, actual) || !assert.Len(t, got, len(want), path) {
+			return
+		}
+		for i := range want {
+			assertJSONSubset(t, want[i], got[i], fmt.Sprintf("%s[%d]", path, i))
+		}
+	default:
+		assert.Equal(t, want, actual, path)
+	}
+}
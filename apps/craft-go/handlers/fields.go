@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fieldSelection is a parsed ?fields= parameter such as
+// "UID,firstName,address.city": each key is a JSON field name, mapped to the
+// selection of its subfields, or to nil when the whole field is selected.
+type fieldSelection map[string]fieldSelection
+
+// requestedFields parses the fields query parameter. It returns nil when the
+// request selects no fields, meaning every field.
+func requestedFields(c *gin.Context) (fieldSelection, error) {
+	raw := strings.TrimSpace(c.Query("fields"))
+	if raw == "" {
+		return nil, nil
+	}
+	selection := fieldSelection{}
+	for _, path := range strings.Split(raw, ",") {
+		names := strings.Split(strings.TrimSpace(path), ".")
+		if slices.Contains(names, "") {
+			return nil, parameterError("fields", fmt.Sprintf("Invalid fields parameter: empty field name in %q", path))
+		}
+		selection.add(names)
+	}
+	return selection, nil
+}
+
+func (s fieldSelection) add(names []string) {
+	subfields, selected := s[names[0]]
+	switch {
+	case len(names) == 1:
+		s[names[0]] = nil
+	case selected && subfields == nil:
+		// The whole field is already selected.
+	default:
+		if subfields == nil {
+			subfields = fieldSelection{}
+			s[names[0]] = subfields
+		}
+		subfields.add(names[1:])
+	}
+}
+
+// check reports an error when the selection names a field that the record
+// type t lacks, so typos fail instead of returning empty objects.
+func (s fieldSelection) check(t reflect.Type) error {
+	return s.checkFields(elemType(t), "")
+}
+
+func (s fieldSelection) checkFields(t reflect.Type, prefix string) error {
+	fields := jsonFields(t)
+	for name, subfields := range s {
+		i := slices.IndexFunc(fields, func(field jsonField) bool { return field.name == name })
+		if i < 0 {
+			return parameterError("fields", fmt.Sprintf("Invalid fields parameter: unknown field %q", prefix+name))
+		}
+		if subfields == nil {
+			continue
+		}
+		nested := elemType(fields[i].typ)
+		if nested.Kind() != reflect.Struct || encodesItself(nested) {
+			return parameterError("fields", fmt.Sprintf("Invalid fields parameter: %q has no subfields", prefix+name))
+		}
+		if err := subfields.checkFields(nested, prefix+name+"."); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// project marshals v with encoding/json and keeps only the selected fields of
+// the records found at path, a dotted list of JSON keys such as
+// "hits.record"; arrays along the way apply it to each element. An empty
+// path means v is the record or an array of records, and a nil selection
+// marshals v unchanged.
+func (s fieldSelection) project(v any, path string) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil || s == nil {
+		return raw, err
+	}
+	var keys []string
+	if path != "" {
+		keys = strings.Split(path, ".")
+	}
+	return s.pruneAt(raw, keys)
+}
+
+func (s fieldSelection) pruneAt(raw []byte, path []string) ([]byte, error) {
+	if len(path) == 0 {
+		return s.prune(raw)
+	}
+	return editJSON(raw, func(key string, value []byte) ([]byte, bool, error) {
+		if key != path[0] {
+			return value, true, nil
+		}
+		pruned, err := s.pruneAt(value, path[1:])
+		return pruned, true, err
+	})
+}
+
+// prune drops the unselected fields of the encoded record raw.
+func (s fieldSelection) prune(raw []byte) ([]byte, error) {
+	return editJSON(raw, func(key string, value []byte) ([]byte, bool, error) {
+		subfields, selected := s[key]
+		if !selected || subfields == nil {
+			return value, selected, nil
+		}
+		pruned, err := subfields.prune(value)
+		return pruned, true, err
+	})
+}
+
+// editJSON rewrites the encoded object raw member by member in order, or
+// each element of the encoded array raw in turn; edit returns the new value
+// of a member and whether to keep it. Other values are returned unchanged.
+func editJSON(raw []byte, edit func(key string, value []byte) ([]byte, bool, error)) ([]byte, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || (raw[0] != '{' && raw[0] != '[') {
+		return raw, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteByte(raw[0])
+	for decoder.More() {
+		var key string
+		if raw[0] == '{' {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			key = token.(string)
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		var edited []byte
+		keep := true
+		var err error
+		if raw[0] == '{' {
+			edited, keep, err = edit(key, value)
+		} else {
+			edited, err = editJSON(value, edit)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !keep {
+			continue
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		if raw[0] == '{' {
+			name, _ := json.Marshal(key)
+			buf.Write(name)
+			buf.WriteByte(':')
+		}
+		buf.Write(edited)
+	}
+	buf.WriteByte(raw[len(raw)-1])
+	return buf.Bytes(), nil
+}
+
+// writeRecordJSON writes v like c.JSON, keeping only the fields selected by
+// a ?fields= parameter in the records of type R found at path in v; see
+// fieldSelection.project.
+func writeRecordJSON[R any](c *gin.Context, status int, v any, path string) {
+	fields, ok := requestedFieldsOf[R](c)
+	if !ok {
+		return
+	}
+	if fields == nil {
+		c.JSON(status, v)
+		return
+	}
+	body, err := fields.project(v, path)
+	if err != nil {
+		abortWithProblem(c, internalError("Failed to encode the response", err))
+		return
+	}
+	c.Data(status, "application/json; charset=utf-8", body)
+}
+
+// requestedFieldsOf reads the ?fields= selection for records of type T
+// before they are written, or before the request changes anything, aborting
+// with a problem and reporting false when it is invalid.
+func requestedFieldsOf[T any](c *gin.Context) (fieldSelection, bool) {
+	fields, err := requestedFields(c)
+	if err == nil {
+		err = fields.check(reflect.TypeFor[T]())
+	}
+	if err != nil {
+		abortWithProblem(c, err)
+		return nil, false
+	}
+	return fields, true
+}
+
+// elemType strips pointers, slices and arrays from t.
+func elemType(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array:
+			t = t.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+// encodesItself reports whether encoding/json leaves the encoding of t to
+// its own MarshalJSON or MarshalText method, as for time.Time.
+func encodesItself(t reflect.Type) bool {
+	pointer := reflect.PointerTo(t)
+	return t.Implements(jsonMarshalerType) || pointer.Implements(jsonMarshalerType) ||
+		t.Implements(textMarshalerType) || pointer.Implements(textMarshalerType)
+}
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// jsonField is a struct field as encoding/json names it.
+type jsonField struct {
+	name string
+	typ  reflect.Type
+}
+
+var jsonFieldCache sync.Map // reflect.Type -> []jsonField
+
+// jsonFields lists the fields encoding/json writes for t, in order, with
+// untagged embedded structs flattened.
+func jsonFields(t reflect.Type) []jsonField {
+	if cached, ok := jsonFieldCache.Load(t); ok {
+		return cached.([]jsonField)
+	}
+	var fields []jsonField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && elemType(field.Type).Kind() == reflect.Struct && field.Type.Kind() != reflect.Slice {
+			fields = append(fields, jsonFields(elemType(field.Type))...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, jsonField{name: name, typ: field.Type})
+	}
+	jsonFieldCache.Store(t, fields)
+	return fields
+}
@@ -1,69 +1,89 @@
 package handlers
 
 import (
+	"craft-fusion/craft-go/generator"
 	"log"
+	"math"
 	"net/http"
 	"strconv"
-	"sync/atomic"
-	"time"
 
-	"craft-fusion/craft-go/models"
-
-	"github.com/brianvoe/gofakeit/v6"
 	"github.com/gin-gonic/gin"
 )
 
-var startTime int64
-
-// GenerateRecordsHandler handles the request to generate multiple records
+// GenerateRecords handles the request to generate multiple records
 // @Summary Generate records
-// @Description Generates fake records in-memory and returns them immediately.
+// @Description Generates fake records in-memory and returns them immediately without storing them.
 // @Tags Records
 // @Produce json
-// @Param count query int false "Number of records to generate" default(10)
-// @Success 200 {array} models.UserRecord
+// @Param count query int false "Number of records to generate (0-1000000)" default(10)
+// @Param seed query int false "Generator seed; omit for a random seed reported in X-Record-Seed"
+// @Param profile query string false "Generator profile, see GET /api-go/records/profiles; omit for the server default"
+// @Param locale query string false "Locale of names, addresses, phones and salary currency, such as de-DE; see GET /api-go/records/profiles"
+// @Param version query string false "Record representation: 2 (canonical Record) or 1 (legacy UserRecord); also read from X-Record-Version" Enums(1, 2) default(2)
+// @Param fields query string false "Comma-separated record fields to return, with dots for nested fields; omit for every field" example(UID,firstName,address.city)
+// @Success 200 {array} models.Record
+// @Header 200 {integer} X-Record-Seed "Seed used to generate the records"
 // @Failure 400 {object} ErrorResponse
 // @Router /api-go/records/generate [get]
-func GenerateRecordsHandler(c *gin.Context) {
-	// Set the start time
-	startTime = time.Now().Unix()
+func (h *RecordHandler) GenerateRecords(c *gin.Context) {
 	// Parse the count parameter
 	count := c.DefaultQuery("count", "10")
 	recordCount, err := strconv.Atoi(count)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid count parameter"})
+	if err != nil || recordCount < 0 {
+		abortWithProblem(c, invalidParameter("count"))
+		return
+	}
+	if recordCount > 1000000 {
+		abortWithProblem(c, limitError("count", "Count cannot exceed 1,000,000 records"))
 		return
 	}
 
-	// Generate the records
-	records := generateRecords(recordCount)
-	endTime := time.Now().Unix()
-	// Calculate the elapsed time
-	elapsedTime := time.Now().UnixMilli() - (startTime * 1000)
-
-	var recordGenerationTime int64
-	atomic.StoreInt64(&recordGenerationTime, elapsedTime)
+	seed, err := seedQuery(c)
+	if err != nil {
+		abortWithProblem(c, err)
+		return
+	}
+	version, err := recordVersion(c)
+	if err != nil {
+		abortWithProblem(c, err)
+		return
+	}
 
-	// Log the number of records generated and the elapsed time
-	log.Printf("Start time: %v, End time: %v", startTime, endTime)
-	log.Printf("%d records generated in: %d ms", recordCount, elapsedTime)
+	// Generate the records and record the run in the generation history
+	records, run, err := h.records.GenerateRecords(recordCount, seed, c.Query("profile"), c.Query("locale"))
+	if err != nil {
+		abortWithProblem(c, asBadRequest(serviceError(err, "Failed to generate records")))
+		return
+	}
+	log.Printf("%d records generated in: %.1f ms (seed %d)", run.Count, run.DurationMs, seed)
 
-	// Return the generated records
-	c.JSON(http.StatusOK, records)
+	// Return the generated records along with the seed that reproduces them
+	c.Header(seedHeader, strconv.FormatInt(seed, 10))
+	renderRecordArray(c, records, version)
 }
 
-// GetCreationTimeHandler handles the request to get the record generation time
+// GetCreationTime handles the request to get the record generation time
 // @Summary Get generation time
-// @Description Returns the latest record generation time in milliseconds.
+// @Description Returns the duration of the latest record generation in milliseconds, or 0 before the first run.
 // @Tags Records
 // @Produce json
 // @Success 200 {object} GenerationTimeResponse
 // @Router /api-go/records/time [get]
 // @Router /api/records/time [get]
-func GetCreationTimeHandler(c *gin.Context) {
-	var recordGenerationTime int64
-	elapsedTime := atomic.LoadInt64(&recordGenerationTime)
-	c.JSON(http.StatusOK, gin.H{"generationTime": elapsedTime})
+func (h *RecordHandler) GetCreationTime(c *gin.Context) {
+	latest, _ := h.records.Stats().Latest()
+	c.JSON(http.StatusOK, GenerationTimeResponse{GenerationTime: int64(math.Round(latest.DurationMs))})
+}
+
+// GetGenerationStats handles the request for the record generation history
+// @Summary Get generation stats
+// @Description Returns recent record generation runs, oldest first, with duration, record count, throughput and seed.
+// @Tags Records
+// @Produce json
+// @Success 200 {object} GenerationStatsResponse
+// @Router /api-go/records/stats [get]
+func (h *RecordHandler) GetGenerationStats(c *gin.Context) {
+	c.JSON(http.StatusOK, GenerationStatsResponse{Runs: h.records.Stats().History()})
 }
 
 // NotImplementedHandler returns a 501 Not Implemented for unimplemented endpoints
@@ -74,46 +94,32 @@ func GetCreationTimeHandler(c *gin.Context) {
 // @Failure 501 {object} ErrorResponse
 // @Router /api/records/generate [get]
 func NotImplementedHandler(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "This endpoint is not implemented in the Go backend. Use the NestJS backend for this route."})
+	abortWithProblem(c, &requestError{
+		problem: problemNotImplemented,
+		detail:  "This endpoint is not implemented in the Go backend. Use the NestJS backend for this route.",
+	})
 }
 
-// Mock function to generate records
-func generateRecords(count int) []models.UserRecord {
-	gofakeit.Seed(0)
-	records := make([]models.UserRecord, count)
-	for i := 0; i < count; i++ {
-		records[i] = models.UserRecord{
-			UID:       strconv.Itoa(gofakeit.Number(100000000, 999999999)),
-			FirstName: gofakeit.FirstName(),
-			LastName:  gofakeit.LastName(),
-			Address: models.Address{
-				Street:  gofakeit.Street(),
-				City:    gofakeit.City(),
-				State:   gofakeit.State(),
-				Zipcode: gofakeit.Zip(),
-			},
-			Phone: models.Phone{
-				Number: gofakeit.Phone(),
-				AreaCode: func() *string {
-					areaCode := strconv.Itoa(gofakeit.Number(200, 999))
-					return &areaCode
-				}(),
-				HasExtension: func() *bool {
-					hasExt := gofakeit.Bool()
-					return &hasExt
-				}(),
-				Extension: func() *string {
-					ext := strconv.Itoa(gofakeit.Number(1000, 9999))
-					return &ext
-				}(),
-			},
-			Salary: []models.Salary{
-				{Amount: float64(gofakeit.Number(50000, 100000)), Year: 2021},
-				{Amount: float64(gofakeit.Number(60000, 120000)), Year: 2022},
-				{Amount: float64(gofakeit.Number(70000, 140000)), Year: 2023},
-			},
-			TotalHouseholdIncome: gofakeit.Number(1000000, 100000000),
-		}
+// GetProfiles lists the generator profiles accepted by ?profile= and the
+// locales accepted by ?locale=
+// @Summary List generator profiles and locales
+// @Description Returns the generator profiles and locales that /api-go/records/generate and /api-go/records/seed accept through `profile` and `locale`, and the profile used when none is named.
+// @Tags Records
+// @Produce json
+// @Success 200 {object} ProfilesResponse
+// @Router /api-go/records/profiles [get]
+func (h *RecordHandler) GetProfiles(c *gin.Context) {
+	profiles := h.records.Profiles()
+	response := ProfilesResponse{Default: profiles.Default().Name(), Profiles: []ProfileInfo{}, Locales: []LocaleInfo{}}
+	for _, profile := range profiles.Profiles() {
+		response.Profiles = append(response.Profiles, ProfileInfo{
+			Name:        profile.Name(),
+			Description: profile.Description(),
+			Coherent:    profile.Coherent(),
+		})
+	}
+	for _, locale := range generator.Locales() {
+		response.Locales = append(response.Locales, LocaleInfo{Tag: locale.Tag(), Name: locale.Name(), Currency: locale.Currency()})
 	}
-	return records
+	c.JSON(http.StatusOK, response)
 }
@@ -1,14 +1,28 @@
 package handlers
 
 import (
+	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"craft-fusion/craft-go/graphqlapi"
 	"craft-fusion/craft-go/models"
+	"craft-fusion/craft-go/repository"
+	"craft-fusion/craft-go/services"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -19,6 +33,7 @@ func init() {
 
 func performRequest(handler gin.HandlerFunc, method, routePath, requestPath string) *httptest.ResponseRecorder {
 	router := gin.New()
+	router.Use(Problems())
 	router.Handle(method, routePath, handler)
 	request := httptest.NewRequest(method, requestPath, nil)
 	response := httptest.NewRecorder()
@@ -26,8 +41,42 @@ func performRequest(handler gin.HandlerFunc, method, routePath, requestPath stri
 	return response
 }
 
+func performJSONRequest(handler gin.HandlerFunc, method, routePath, requestPath, body string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Use(Problems())
+	router.Handle(method, routePath, handler)
+	request := httptest.NewRequest(method, requestPath, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	return response
+}
+
+// decodeProblem checks that response is a problem with status and returns it.
+func decodeProblem(t *testing.T, response *httptest.ResponseRecorder, status int) ErrorResponse {
+	t.Helper()
+	require.Equal(t, status, response.Code, response.Body.String())
+	assert.Equal(t, problemContentType, response.Header().Get("Content-Type"))
+	var problem ErrorResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &problem))
+	assert.Equal(t, status, problem.Status)
+	assert.NotEmpty(t, problem.CorrelationID)
+	assert.Equal(t, response.Header().Get(CorrelationHeader), problem.CorrelationID)
+	return problem
+}
+
+func newTestRecordHandler(t *testing.T, seedCount int) *RecordHandler {
+	t.Helper()
+	service := services.NewRecordService(repository.NewMemoryStore())
+	_, err := service.SeedIfEmpty(seedCount, 0)
+	require.NoError(t, err)
+	return NewRecordHandler(service)
+}
+
 func TestGetRecordsHandlerReturnsMockDataset(t *testing.T) {
-	response := performRequest(GetRecordsHandler, http.MethodGet, "/records", "/records?limit=3")
+	t.Parallel()
+	handler := newTestRecordHandler(t, 10)
+	response := performRequest(handler.GetRecords, http.MethodGet, "/records", "/records?limit=3")
 
 	require.Equal(t, http.StatusOK, response.Code)
 	var body struct {
@@ -40,47 +89,385 @@ func TestGetRecordsHandlerReturnsMockDataset(t *testing.T) {
 }
 
 func TestGetRecordsHandlerValidatesLimit(t *testing.T) {
+	t.Parallel()
+	handler := newTestRecordHandler(t, 10)
 	tests := []string{"/records?limit=invalid", "/records?limit=0", "/records?limit=1000001"}
 
 	for _, path := range tests {
 		t.Run(path, func(t *testing.T) {
-			response := performRequest(GetRecordsHandler, http.MethodGet, "/records", path)
-			assert.Equal(t, http.StatusBadRequest, response.Code)
-			assert.Contains(t, response.Body.String(), "error")
+			response := performRequest(handler.GetRecords, http.MethodGet, "/records", path)
+			problem := decodeProblem(t, response, http.StatusBadRequest)
+			assert.Equal(t, "limit", problem.Errors[0].Field)
 		})
 	}
 }
 
+func TestGetRecordsHandlerPagesAndSorts(t *testing.T) {
+	t.Parallel()
+	handler := newTestRecordHandler(t, 20)
+	response := performRequest(handler.GetRecords, http.MethodGet, "/records",
+		"/records?limit=3&page=2&pageSize=5&sort=-totalHouseholdIncome")
+
+	require.Equal(t, http.StatusOK, response.Code)
+	var body RecordsResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+	require.Len(t, body.Records, 5)
+	assert.Equal(t, 20, body.Total)
+	assert.Equal(t, 2, body.Page)
+	for i := 1; i < len(body.Records); i++ {
+		assert.GreaterOrEqual(t, body.Records[i-1].TotalHouseholdIncome, body.Records[i].TotalHouseholdIncome)
+	}
+	assert.Contains(t, body.Links.Next, "page=3")
+	assert.Contains(t, body.Links.Prev, "page=1")
+	assert.Contains(t, body.Links.Self, "sort=-totalHouseholdIncome")
+
+	invalid := performRequest(handler.GetRecords, http.MethodGet, "/records", "/records?sort=password")
+	assert.Equal(t, http.StatusBadRequest, invalid.Code)
+	invalidPage := performRequest(handler.GetRecords, http.MethodGet, "/records", "/records?page=-1")
+	assert.Equal(t, http.StatusBadRequest, invalidPage.Code)
+	for _, path := range []string{"/records?page=4611686018427387904&pageSize=4", "/records?page=4611686018427387904&pageSize=4&sort=lastName", "/records?pageSize=1000001"} {
+		overflow := performRequest(handler.GetRecords, http.MethodGet, "/records", path)
+		assert.Equal(t, http.StatusBadRequest, overflow.Code, path)
+	}
+}
+
+func TestGetRecordsHandlerStreamsLargeListings(t *testing.T) {
+	t.Parallel()
+	handler := newTestRecordHandler(t, 2500)
+	buffered := performRequest(handler.GetRecords, http.MethodGet, "/records", "/records?limit=2500")
+	require.Equal(t, http.StatusOK, buffered.Code)
+
+	streamed := performRequest(handler.GetRecords, http.MethodGet, "/records", "/records?limit=2500&stream=true")
+	require.Equal(t, http.StatusOK, streamed.Code)
+	assert.Equal(t, "application/json; charset=utf-8", streamed.Header().Get("Content-Type"))
+	var bufferedBody, streamedBody RecordsResponse
+	require.NoError(t, json.Unmarshal(buffered.Body.Bytes(), &bufferedBody))
+	require.NoError(t, json.Unmarshal(streamed.Body.Bytes(), &streamedBody))
+	assert.Contains(t, streamedBody.Links.Self, "stream=true")
+	streamedBody.Links = bufferedBody.Links
+	assert.Equal(t, bufferedBody, streamedBody)
+
+	router := gin.New()
+	router.GET("/records", handler.GetRecords)
+	request := httptest.NewRequest(http.MethodGet, "/records?limit=2500", nil)
+	request.Header.Set("Accept", "application/x-ndjson")
+	ndjson := httptest.NewRecorder()
+	router.ServeHTTP(ndjson, request)
+	require.Equal(t, http.StatusOK, ndjson.Code)
+	assert.Equal(t, "application/x-ndjson", ndjson.Header().Get("Content-Type"))
+	assert.True(t, ndjson.Flushed)
+
+	lines := strings.Split(strings.TrimSpace(ndjson.Body.String()), "\n")
+	require.Len(t, lines, 2500)
+	var last models.Record
+	require.NoError(t, json.Unmarshal([]byte(lines[2499]), &last))
+	assert.Equal(t, bufferedBody.Records[2499], last)
+
+	paged := performRequest(handler.GetRecords, http.MethodGet, "/records", "/records?pageSize=10&stream=ndjson")
+	assert.Len(t, strings.Split(strings.TrimSpace(paged.Body.String()), "\n"), 10)
+}
+
+// failingWriter accepts limit bytes and fails every later write.
+type failingWriter struct {
+	*httptest.ResponseRecorder
+	limit  int
+	failed int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.Body.Len()+len(p) > w.limit {
+		w.failed++
+		return 0, errors.New("connection reset")
+	}
+	return w.ResponseRecorder.Write(p)
+}
+
+func TestRecordStreamsStopOnWriteFailure(t *testing.T) {
+	t.Parallel()
+	handler := newTestRecordHandler(t, 50)
+	router := gin.New()
+	router.GET("/records", handler.GetRecords)
+
+	for _, path := range []string{"/records?stream=true", "/records?stream=ndjson", "/records?stream=true&fields=UID"} {
+		t.Run(path, func(t *testing.T) {
+			writer := &failingWriter{ResponseRecorder: httptest.NewRecorder(), limit: 600}
+			router.ServeHTTP(writer, httptest.NewRequest(http.MethodGet, path, nil))
+			assert.Equal(t, 1, writer.failed)
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cancelled := httptest.NewRecorder()
+	router.ServeHTTP(cancelled, httptest.NewRequest(http.MethodGet, "/records?stream=true", nil).WithContext(ctx))
+	assert.Empty(t, cancelled.Body.String())
+}
+
+// scanCountingStore counts the records its scans have visited.
+type scanCountingStore struct {
+	repository.RecordStore
+	visited atomic.Int64
+}
+
+func (s *scanCountingStore) Scan(visit func(models.Record) bool) {
+	s.RecordStore.Scan(func(record models.Record) bool {
+		s.visited.Add(1)
+		return visit(record)
+	})
+}
+
+// flushRecorder notes how many records the store had visited when the first
+// records were flushed.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	store            *scanCountingStore
+	visitedAtFlushes []int64
+}
+
+func (w *flushRecorder) Flush() {
+	if w.Body.Len() > 0 {
+		w.visitedAtFlushes = append(w.visitedAtFlushes, w.store.visited.Load())
+	}
+	w.ResponseRecorder.Flush()
+}
+
+func TestRecordStreamsWriteWhileScanning(t *testing.T) {
+	t.Parallel()
+	store := &scanCountingStore{RecordStore: repository.NewMemoryStore()}
+	require.NoError(t, store.Replace(repository.GenerateMockRecords(3*streamFlushInterval, 1)))
+	router := gin.New()
+	router.GET("/records", NewRecordHandler(services.NewRecordService(store)).GetRecords)
+
+	for _, path := range []string{"/records?limit=1000000&stream=ndjson", "/records?limit=1000000&stream=true&version=1"} {
+		store.visited.Store(0)
+		writer := &flushRecorder{ResponseRecorder: httptest.NewRecorder(), store: store}
+		router.ServeHTTP(writer, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, writer.Code)
+		require.NotEmpty(t, writer.visitedAtFlushes, path)
+		assert.Equal(t, int64(streamFlushInterval), writer.visitedAtFlushes[0], path)
+	}
+}
+
+func TestListingKeepsDatasetStableUntilSeeded(t *testing.T) {
+	t.Parallel()
+	handler := newTestRecordHandler(t, 5)
+	list := func() []models.Record {
+		response := performRequest(handler.GetRecords, http.MethodGet, "/records", "/records")
+		require.Equal(t, http.StatusOK, response.Code)
+		var body RecordsResponse
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+		return body.Records
+	}
+
+	first := list()
+	require.Len(t, first, 5)
+	assert.Equal(t, first, list())
+
+	seeded := performRequest(handler.SeedRecords, http.MethodPost, "/records/seed", "/records/seed?count=7&seed=99")
+	require.Equal(t, http.StatusOK, seeded.Code)
+	assert.JSONEq(t, `{"count":7,"seed":99}`, seeded.Body.String())
+	assert.Equal(t, "99", seeded.Header().Get("X-Record-Seed"))
+	second := list()
+	require.Len(t, second, 7)
+	assert.NotEqual(t, first[0].UID, second[0].UID)
+
+	for _, path := range []string{"/records/seed?count=0", "/records/seed?count=1000001", "/records/seed?count=x", "/records/seed?seed=x"} {
+		invalid := performRequest(handler.SeedRecords, http.MethodPost, "/records/seed", path)
+		assert.Equal(t, http.StatusBadRequest, invalid.Code, path)
+	}
+}
+
 func TestGetRecordByUIDHandler(t *testing.T) {
-	listResponse := performRequest(GetRecordsHandler, http.MethodGet, "/records", "/records?limit=1")
+	t.Parallel()
+	handler := newTestRecordHandler(t, 10)
+	listResponse := performRequest(handler.GetRecords, http.MethodGet, "/records", "/records?limit=1")
 	var listBody struct {
 		Records []models.Record `json:"records"`
 	}
 	require.NoError(t, json.Unmarshal(listResponse.Body.Bytes(), &listBody))
 	require.Len(t, listBody.Records, 1)
 
-	response := performRequest(GetRecordByUIDHandler, http.MethodGet, "/records/:UID", "/records/"+listBody.Records[0].UID)
+	response := performRequest(handler.GetRecordByUID, http.MethodGet, "/records/:UID", "/records/"+listBody.Records[0].UID)
 	assert.Equal(t, http.StatusOK, response.Code)
 	assert.Contains(t, response.Body.String(), listBody.Records[0].UID)
 
-	missing := performRequest(GetRecordByUIDHandler, http.MethodGet, "/records/:UID", "/records/missing")
+	missing := performRequest(handler.GetRecordByUID, http.MethodGet, "/records/:UID", "/records/missing")
 	assert.Equal(t, http.StatusNotFound, missing.Code)
 }
 
 func TestGenerateRecordHandlers(t *testing.T) {
-	response := performRequest(GenerateRecordsHandler, http.MethodGet, "/records/generate", "/records/generate?count=2")
+	t.Parallel()
+	handler := newTestRecordHandler(t, 0)
+	response := performRequest(handler.GenerateRecords, http.MethodGet, "/records/generate", "/records/generate?count=2")
 	assert.Equal(t, http.StatusOK, response.Code)
-	var records []models.UserRecord
+	var records []models.Record
 	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &records))
 	assert.Len(t, records, 2)
+	assert.Equal(t, "2", response.Header().Get("X-Record-Version"))
 
-	invalid := performRequest(GenerateRecordsHandler, http.MethodGet, "/records/generate", "/records/generate?count=invalid")
+	invalid := performRequest(handler.GenerateRecords, http.MethodGet, "/records/generate", "/records/generate?count=invalid")
 	assert.Equal(t, http.StatusBadRequest, invalid.Code)
 
 	newRecords := performRequest(GenerateNewRecordsHandler, http.MethodGet, "/records/new", "/records/new?count=2")
 	assert.Equal(t, http.StatusOK, newRecords.Code)
 }
 
+func TestGenerateRecordsHandlerIsReproducibleFromSeed(t *testing.T) {
+	t.Parallel()
+	handler := newTestRecordHandler(t, 0)
+	first := performRequest(handler.GenerateRecords, http.MethodGet, "/records/generate", "/records/generate?count=5&seed=42")
+	second := performRequest(handler.GenerateRecords, http.MethodGet, "/records/generate", "/records/generate?count=5&seed=42")
+	require.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "42", first.Header().Get("X-Record-Seed"))
+	assert.Equal(t, first.Body.Bytes(), second.Body.Bytes())
+
+	random := performRequest(handler.GenerateRecords, http.MethodGet, "/records/generate", "/records/generate?count=5")
+	replaySeed := random.Header().Get("X-Record-Seed")
+	require.NotEmpty(t, replaySeed)
+	replay := performRequest(handler.GenerateRecords, http.MethodGet, "/records/generate", "/records/generate?count=5&seed="+replaySeed)
+	assert.Equal(t, random.Body.Bytes(), replay.Body.Bytes())
+}
+
+func TestGenerateRecordsHandlerSelectsProfile(t *testing.T) {
+	t.Parallel()
+	handler := newTestRecordHandler(t, 0)
+	response := performRequest(handler.GenerateRecords, http.MethodGet, "/records/generate", "/records/generate?count=3&seed=5&profile=classic")
+	require.Equal(t, http.StatusOK, response.Code)
+	var records []models.Record
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &records))
+	require.Len(t, records, 3)
+	for _, record := range records {
+		assert.Empty(t, record.Email)
+		assert.Len(t, record.Salary, 1)
+	}
+
+	invalid := performRequest(handler.GenerateRecords, http.MethodGet, "/records/generate", "/records/generate?profile=missing")
+	problem := decodeProblem(t, invalid, http.StatusBadRequest)
+	require.Len(t, problem.Errors, 1)
+	assert.Equal(t, "profile", problem.Errors[0].Field)
+	seeded := performRequest(handler.SeedRecords, http.MethodPost, "/records/seed", "/records/seed?profile=missing")
+	assert.Equal(t, http.StatusBadRequest, seeded.Code)
+
+	localized := performRequest(handler.GenerateRecords, http.MethodGet, "/records/generate", "/records/generate?count=2&seed=5&locale=fr-FR")
+	require.Equal(t, http.StatusOK, localized.Code)
+	require.NoError(t, json.Unmarshal(localized.Body.Bytes(), &records))
+	require.NotNil(t, records[0].Phone.CountryCode)
+	assert.Equal(t, "33", *records[0].Phone.CountryCode)
+	require.NotNil(t, records[0].Salary[0].Currency)
+	assert.Equal(t, "EUR", *records[0].Salary[0].Currency)
+
+	invalidLocale := performRequest(handler.GenerateRecords, http.MethodGet, "/records/generate", "/records/generate?locale=xx-XX")
+	problem = decodeProblem(t, invalidLocale, http.StatusBadRequest)
+	assert.Equal(t, `Unknown generator locale: "xx-XX"`, problem.Detail)
+	seededLocale := performRequest(handler.SeedRecords, http.MethodPost, "/records/seed", "/records/seed?locale=xx-XX")
+	problem = decodeProblem(t, seededLocale, http.StatusBadRequest)
+	assert.Equal(t, []repository.FieldError{{Field: "locale", Message: `unknown generator locale: "xx-XX"`}}, problem.Errors)
+
+	profiles := performRequest(handler.GetProfiles, http.MethodGet, "/records/profiles", "/records/profiles")
+	require.Equal(t, http.StatusOK, profiles.Code)
+	var listed ProfilesResponse
+	require.NoError(t, json.Unmarshal(profiles.Body.Bytes(), &listed))
+	assert.Equal(t, "full", listed.Default)
+	require.Len(t, listed.Profiles, 3)
+	assert.Equal(t, "classic", listed.Profiles[0].Name)
+	assert.NotEmpty(t, listed.Profiles[0].Description)
+	assert.False(t, listed.Profiles[0].Coherent)
+	assert.True(t, listed.Profiles[1].Coherent)
+	require.Len(t, listed.Locales, 4)
+	assert.Equal(t, LocaleInfo{Tag: "ja-JP", Name: "日本語 (日本)", Currency: "JPY"}, listed.Locales[3])
+}
+
+func TestRecordEndpointsRenderLegacyVersion(t *testing.T) {
+	t.Parallel()
+	handler := newTestRecordHandler(t, 3)
+
+	current := performRequest(handler.GetRecords, http.MethodGet, "/records", "/records")
+	var canonical RecordsResponse
+	require.NoError(t, json.Unmarshal(current.Body.Bytes(), &canonical))
+	require.Len(t, canonical.Records, 3)
+	record := canonical.Records[0]
+
+	legacyList := performRequest(handler.GetRecords, http.MethodGet, "/records", "/records?version=1")
+	require.Equal(t, http.StatusOK, legacyList.Code)
+	assert.Equal(t, "1", legacyList.Header().Get("X-Record-Version"))
+	var legacy LegacyRecordsResponse
+	require.NoError(t, json.Unmarshal(legacyList.Body.Bytes(), &legacy))
+	require.Len(t, legacy.Records, 3)
+	assert.Equal(t, canonical.Total, legacy.Total)
+	assert.Contains(t, legacy.Links.Self, "version=1")
+	assert.Equal(t, models.NewUserRecord(record), legacy.Records[0])
+	assert.Equal(t, int(math.Round(record.TotalHouseholdIncome)), legacy.Records[0].TotalHouseholdIncome)
+	assert.Equal(t, record.Salary[0].AnnualSalary, legacy.Records[0].Salary[0].Amount)
+
+	router := gin.New()
+	router.GET("/records/:UID", handler.GetRecordByUID)
+	request := httptest.NewRequest(http.MethodGet, "/records/"+record.UID, nil)
+	request.Header.Set("X-Record-Version", "1")
+	single := httptest.NewRecorder()
+	router.ServeHTTP(single, request)
+	require.Equal(t, http.StatusOK, single.Code)
+	assert.NotContains(t, single.Body.String(), "companyName")
+
+	streamed := performRequest(handler.GetRecords, http.MethodGet, "/records", "/records?version=1&stream=ndjson")
+	assert.NotContains(t, streamed.Body.String(), "companyName")
+	assert.Len(t, strings.Split(strings.TrimSpace(streamed.Body.String()), "\n"), 3)
+
+	invalid := performRequest(handler.GetRecords, http.MethodGet, "/records", "/records?version=3")
+	assert.Equal(t, http.StatusBadRequest, invalid.Code)
+}
+
+func TestLegacyVersionKeepsSalaryCurrency(t *testing.T) {
+	t.Parallel()
+	handler := newTestRecordHandler(t, 0)
+	seeded := performRequest(handler.SeedRecords, http.MethodPost, "/records/seed", "/records/seed?count=3&seed=8&locale=ja-JP")
+	require.Equal(t, http.StatusOK, seeded.Code, seeded.Body.String())
+
+	response := performRequest(handler.GetRecords, http.MethodGet, "/records", "/records?version=1")
+	require.Equal(t, http.StatusOK, response.Code)
+	var legacy LegacyRecordsResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &legacy))
+	salaries := 0
+	for _, record := range legacy.Records {
+		for _, salary := range record.Salary {
+			assert.Equal(t, "JPY", salary.Currency)
+			salaries++
+		}
+	}
+	assert.Positive(t, salaries)
+
+	unset := models.NewUserRecord(models.Record{Salary: []models.Company{{CompanyName: "Acme", AnnualSalary: 1}}})
+	assert.Equal(t, models.DefaultCurrency, unset.Salary[0].Currency)
+}
+
+func TestGenerationTimingEndpoints(t *testing.T) {
+	t.Parallel()
+	handler := newTestRecordHandler(t, 0)
+
+	before := performRequest(handler.GetCreationTime, http.MethodGet, "/records/time", "/records/time")
+	assert.JSONEq(t, `{"generationTime":0}`, before.Body.String())
+
+	performRequest(handler.SeedRecords, http.MethodPost, "/records/seed", "/records/seed?count=5000&seed=3")
+	performRequest(handler.GenerateRecords, http.MethodGet, "/records/generate", "/records/generate?count=4&seed=4")
+
+	var stats GenerationStatsResponse
+	response := performRequest(handler.GetGenerationStats, http.MethodGet, "/records/stats", "/records/stats")
+	require.Equal(t, http.StatusOK, response.Code)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &stats))
+	require.Len(t, stats.Runs, 2)
+	assert.Equal(t, "seed", stats.Runs[0].Source)
+	assert.Equal(t, 5000, stats.Runs[0].Count)
+	assert.Equal(t, int64(3), stats.Runs[0].Seed)
+	assert.Positive(t, stats.Runs[0].DurationMs)
+	assert.Positive(t, stats.Runs[0].RecordsPerSecond)
+	assert.Equal(t, "generate", stats.Runs[1].Source)
+	assert.Equal(t, 4, stats.Runs[1].Count)
+
+	var timing GenerationTimeResponse
+	latest := performRequest(handler.GetCreationTime, http.MethodGet, "/records/time", "/records/time")
+	require.NoError(t, json.Unmarshal(latest.Body.Bytes(), &timing))
+	assert.Equal(t, int64(math.Round(stats.Runs[1].DurationMs)), timing.GenerationTime)
+}
+
 func TestHealthAndCompatibilityHandlers(t *testing.T) {
 	health := performRequest(HealthHandler, http.MethodGet, "/health", "/health")
 	assert.Equal(t, http.StatusOK, health.Code)
@@ -90,3 +477,560 @@ func TestHealthAndCompatibilityHandlers(t *testing.T) {
 	assert.Equal(t, http.StatusNotImplemented, notImplemented.Code)
 	assert.Contains(t, notImplemented.Body.String(), "not implemented")
 }
+
+func TestRejectedPatchLeavesRecordUnchanged(t *testing.T) {
+	t.Parallel()
+	handler := newTestRecordHandler(t, 0)
+	created := performJSONRequest(handler.CreateRecord, http.MethodPost, "/records", "/records",
+		`{"UID":"patch-1","firstName":"Ada","lastName":"Lovelace","phone":{"number":"555-0100","extension":"12"},`+
+			`"salary":[{"companyName":"Analytical Engines","annualSalary":1000,"companyPosition":"Analyst"}]}`)
+	require.Equal(t, http.StatusCreated, created.Code)
+	before := performRequest(handler.GetRecordByUID, http.MethodGet, "/records/:UID", "/records/patch-1")
+	require.Equal(t, http.StatusOK, before.Code)
+
+	for _, body := range []string{
+		`{"phone":{"extension":"99"},"salary":[{"companyName":"","annualSalary":5,"companyPosition":"Clerk"}]}`,
+		`{"phone":{"extension":"99"},"UID":"other"}`,
+	} {
+		rejected := performJSONRequest(handler.PatchRecord, http.MethodPatch, "/records/:UID", "/records/patch-1", body)
+		require.Equal(t, http.StatusUnprocessableEntity, rejected.Code, body)
+		after := performRequest(handler.GetRecordByUID, http.MethodGet, "/records/:UID", "/records/patch-1")
+		assert.Equal(t, before.Body.String(), after.Body.String(), body)
+	}
+}
+
+func TestPatchReplacesArrays(t *testing.T) {
+	t.Parallel()
+	handler := newTestRecordHandler(t, 0)
+	created := performJSONRequest(handler.CreateRecord, http.MethodPost, "/records", "/records",
+		`{"UID":"patch-2","firstName":"Ada","lastName":"Lovelace","phone":{"number":"555-0100","extension":"12"},`+
+			`"salary":[{"companyName":"Analytical Engines","annualSalary":1000,"companyPosition":"Analyst","currency":"GBP"},`+
+			`{"companyName":"Difference Works","annualSalary":500}]}`)
+	require.Equal(t, http.StatusCreated, created.Code)
+
+	patched := performJSONRequest(handler.PatchRecord, http.MethodPatch, "/records/:UID", "/records/patch-2",
+		`{"salary":[{"companyName":"X"}],"phone":{"extension":null}}`)
+	require.Equal(t, http.StatusOK, patched.Code, patched.Body.String())
+	var record models.Record
+	require.NoError(t, json.Unmarshal(patched.Body.Bytes(), &record))
+	assert.Equal(t, []models.Company{{CompanyName: "X"}}, record.Salary)
+	assert.Equal(t, "555-0100", record.Phone.Number)
+	assert.Nil(t, record.Phone.Extension)
+	assert.Equal(t, "Lovelace", record.LastName)
+
+	for _, body := range []string{`[]`, `{"firstName":"Ada"} {}`} {
+		malformed := performJSONRequest(handler.PatchRecord, http.MethodPatch, "/records/:UID", "/records/patch-2", body)
+		assert.Equal(t, http.StatusBadRequest, malformed.Code, body)
+	}
+}
+
+func TestRecordCRUDHandlers(t *testing.T) {
+	t.Parallel()
+	handler := newTestRecordHandler(t, 10)
+
+	created := performJSONRequest(handler.CreateRecord, http.MethodPost, "/records", "/records",
+		`{"UID":"crud-1","firstName":"Ada","lastName":"Lovelace","address":{"city":"London"}}`)
+	require.Equal(t, http.StatusCreated, created.Code)
+	assert.Equal(t, "/records/crud-1", created.Header().Get("Location"))
+
+	conflict := performJSONRequest(handler.CreateRecord, http.MethodPost, "/records", "/records",
+		`{"UID":"crud-1","firstName":"Ada","lastName":"Lovelace"}`)
+	assert.Equal(t, http.StatusConflict, conflict.Code)
+
+	invalid := performJSONRequest(handler.CreateRecord, http.MethodPost, "/records", "/records",
+		`{"lastName":"Lovelace","salary":[{"companyName":"","annualSalary":-1}]}`)
+	problem := decodeProblem(t, invalid, http.StatusUnprocessableEntity)
+	assert.Equal(t, "https://jeffreysanford.us/problems/validation", problem.Type)
+	assert.Contains(t, problem.Detail, "firstName is required")
+	assert.Contains(t, problem.Errors, repository.FieldError{Field: "firstName", Message: "is required"})
+	assert.Contains(t, problem.Errors, repository.FieldError{Field: "salary[0].annualSalary", Message: "must be gte=0"})
+
+	malformed := performJSONRequest(handler.CreateRecord, http.MethodPost, "/records", "/records", `{"firstName":`)
+	assert.Equal(t, http.StatusBadRequest, malformed.Code)
+
+	mismatch := performJSONRequest(handler.UpdateRecord, http.MethodPut, "/records/:UID", "/records/crud-1",
+		`{"UID":"other","firstName":"Ada","lastName":"King"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, mismatch.Code)
+
+	updated := performJSONRequest(handler.UpdateRecord, http.MethodPut, "/records/:UID", "/records/crud-1",
+		`{"firstName":"Ada","lastName":"King"}`)
+	require.Equal(t, http.StatusOK, updated.Code)
+	assert.NotContains(t, updated.Body.String(), "London")
+
+	patched := performJSONRequest(handler.PatchRecord, http.MethodPatch, "/records/:UID", "/records/crud-1",
+		`{"address":{"city":"Marylebone"}}`)
+	require.Equal(t, http.StatusOK, patched.Code)
+	var record models.Record
+	require.NoError(t, json.Unmarshal(patched.Body.Bytes(), &record))
+	assert.Equal(t, "King", record.LastName)
+	assert.Equal(t, "Marylebone", record.Address.City)
+
+	invalidPatch := performJSONRequest(handler.PatchRecord, http.MethodPatch, "/records/:UID", "/records/crud-1", `{"firstName":""}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, invalidPatch.Code)
+
+	missingPatch := performJSONRequest(handler.PatchRecord, http.MethodPatch, "/records/:UID", "/records/missing", `{}`)
+	assert.Equal(t, http.StatusNotFound, missingPatch.Code)
+
+	deleted := performRequest(handler.DeleteRecord, http.MethodDelete, "/records/:UID", "/records/crud-1")
+	assert.Equal(t, http.StatusNoContent, deleted.Code)
+	deletedAgain := performRequest(handler.DeleteRecord, http.MethodDelete, "/records/:UID", "/records/crud-1")
+	assert.Equal(t, http.StatusNotFound, deletedAgain.Code)
+}
+
+func TestAggregateRecordsHandler(t *testing.T) {
+	t.Parallel()
+	handler := newTestRecordHandler(t, 50)
+
+	response := performRequest(handler.AggregateRecords, http.MethodGet, "/records/aggregate", "/records/aggregate?groupBy=state&metrics=count,avgIncome,p90Income&limit=3")
+	require.Equal(t, http.StatusOK, response.Code)
+	var result services.AggregateResult
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &result))
+	assert.Equal(t, []string{"state"}, result.GroupBy)
+	require.Len(t, result.Groups, 3)
+	assert.GreaterOrEqual(t, result.Groups[0].Count, result.Groups[2].Count)
+	assert.Contains(t, result.Groups[0].Metrics, "p90Income")
+
+	response = performRequest(handler.AggregateRecords, http.MethodGet, "/records/aggregate", "/records/aggregate?groupBy=email")
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	response = performRequest(handler.AggregateRecords, http.MethodGet, "/records/aggregate", "/records/aggregate?bucketSize=wide")
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestSearchRecordsHandler(t *testing.T) {
+	t.Parallel()
+	handler := newTestRecordHandler(t, 0)
+	response := performJSONRequest(handler.CreateRecord, http.MethodPost, "/records", "/records",
+		`{"UID":"s-1","firstName":"Ann","lastName":"Smith","address":{"city":"Denver"}}`)
+	require.Equal(t, http.StatusCreated, response.Code)
+
+	response = performRequest(handler.SearchRecords, http.MethodGet, "/records/search", "/records/search?q=smith+den")
+	require.Equal(t, http.StatusOK, response.Code)
+	var body SearchResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+	assert.Equal(t, "smith den", body.Query)
+	require.Equal(t, 1, body.Total)
+	assert.Equal(t, "s-1", body.Hits[0].Record.UID)
+	assert.Len(t, body.Hits[0].Highlights, 2)
+
+	response = performRequest(handler.SearchRecords, http.MethodGet, "/records/search", "/records/search")
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	response = performRequest(handler.SearchRecords, http.MethodGet, "/records/search", "/records/search?q=a&limit=0")
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestExportRecordsHandler(t *testing.T) {
+	t.Parallel()
+	handler := newTestRecordHandler(t, 20)
+
+	response := performRequest(handler.ExportRecords, http.MethodGet, "/records/export", "/records/export?sort=lastName&pageSize=5")
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", response.Header().Get("Content-Type"))
+	assert.Regexp(t, `^attachment; filename="records-\d{8}-\d{6}\.csv"$`, response.Header().Get("Content-Disposition"))
+	rows, err := csv.NewReader(response.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 6)
+	assert.Contains(t, rows[0], "salary[0].companyName")
+
+	response = performRequest(handler.ExportRecords, http.MethodGet, "/records/export", "/records/export?format=xlsx")
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "PK", response.Body.String()[:2])
+
+	response = performRequest(handler.ExportRecords, http.MethodGet, "/records/export", "/records/export?limit=3&format=ndjson")
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Len(t, strings.Split(strings.TrimSpace(response.Body.String()), "\n"), 3)
+	response = performRequest(handler.ExportRecords, http.MethodGet, "/records/export", "/records/export")
+	rows, err = csv.NewReader(response.Body).ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, rows, 21)
+
+	for _, path := range []string{"/records/export?format=pdf", "/records/export?sort=avatar", "/records/export?limit=0", "/records/export?limit=1000001"} {
+		response = performRequest(handler.ExportRecords, http.MethodGet, "/records/export", path)
+		assert.Equal(t, http.StatusBadRequest, response.Code, path)
+	}
+}
+
+func TestExportWritesWhileScanning(t *testing.T) {
+	t.Parallel()
+	store := &scanCountingStore{RecordStore: repository.NewMemoryStore()}
+	require.NoError(t, store.Replace(repository.GenerateMockRecords(3*streamFlushInterval, 1)))
+	router := gin.New()
+	router.GET("/records/export", NewRecordHandler(services.NewRecordService(store)).ExportRecords)
+
+	writer := &flushRecorder{ResponseRecorder: httptest.NewRecorder(), store: store}
+	router.ServeHTTP(writer, httptest.NewRequest(http.MethodGet, "/records/export", nil))
+	require.Equal(t, http.StatusOK, writer.Code)
+	require.NotEmpty(t, writer.visitedAtFlushes)
+	// The first pass finds the company slots; rows follow the second.
+	assert.Equal(t, int64(4*streamFlushInterval), writer.visitedAtFlushes[0])
+}
+
+func TestImportRecordsHandler(t *testing.T) {
+	t.Parallel()
+	handler := newTestRecordHandler(t, 3)
+	importCSV := func(query, body string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.POST("/records/import", handler.ImportRecords)
+		request := httptest.NewRequest(http.MethodPost, "/records/import"+query, strings.NewReader(body))
+		request.Header.Set("Content-Type", "text/csv; charset=utf-8")
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response
+	}
+
+	response := importCSV("?dryRun=true", "UID,firstName,lastName\nimp-1,Ann,Smith\n")
+	require.Equal(t, http.StatusOK, response.Code)
+	var result services.ImportResult
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &result))
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, http.StatusNotFound, performRequest(handler.GetRecordByUID, http.MethodGet, "/records/:UID", "/records/imp-1").Code)
+
+	response = importCSV("?mode=replace", "UID,firstName,lastName\nimp-1,Ann,Smith\n")
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, http.StatusOK, performRequest(handler.GetRecordByUID, http.MethodGet, "/records/:UID", "/records/imp-1").Code)
+
+	response = importCSV("", "UID,firstName\nimp-2,Bob\n")
+	problem := decodeProblem(t, response, http.StatusUnprocessableEntity)
+	assert.Equal(t, "Import rejected: 1 of 1 lines are invalid", problem.Detail)
+	assert.Equal(t, []repository.FieldError{{Field: "line 2", Message: "lastName is required"}}, problem.Errors)
+
+	response = importCSV("?mode=replace", "UID,firstName,lastName\n")
+	problem = decodeProblem(t, response, http.StatusBadRequest)
+	assert.Equal(t, "allowEmpty", problem.Errors[0].Field)
+	assert.Equal(t, http.StatusOK, performRequest(handler.GetRecordByUID, http.MethodGet, "/records/:UID", "/records/imp-1").Code)
+	assert.Equal(t, http.StatusOK, importCSV("?mode=replace&allowEmpty=true", "UID,firstName,lastName\n").Code)
+
+	assert.Equal(t, http.StatusBadRequest, importCSV("", "UID,avatar\n").Code)
+	assert.Equal(t, http.StatusBadRequest, importCSV("?mode=append", "UID\n").Code)
+	assert.Equal(t, http.StatusBadRequest, importCSV("?format=xlsx", "UID\n").Code)
+}
+
+func TestGenerationJobHandlers(t *testing.T) {
+	t.Parallel()
+	handler := newTestRecordHandler(t, 0)
+
+	started := performRequest(handler.StartGenerationJob, http.MethodPost, "/records/jobs", "/records/jobs?count=9000&seed=4&locale=de-DE")
+	require.Equal(t, http.StatusAccepted, started.Code)
+	var job services.GenerationJob
+	require.NoError(t, json.Unmarshal(started.Body.Bytes(), &job))
+	assert.Equal(t, "/api-go/records/jobs/"+job.ID, started.Header().Get("Location"))
+	assert.Equal(t, "de-DE", job.Locale)
+
+	// The event stream ends once the job finishes.
+	events := performRequest(handler.StreamGenerationJob, http.MethodGet, "/records/jobs/:id/events", "/records/jobs/"+job.ID+"/events")
+	require.Equal(t, http.StatusOK, events.Code)
+	assert.True(t, strings.HasPrefix(events.Header().Get("Content-Type"), "text/event-stream"))
+	body := events.Body.String()
+	assert.True(t, strings.HasPrefix(body, "event:progress\ndata:{"), body)
+	assert.Contains(t, body, "event:completed\ndata:{")
+	assert.True(t, strings.HasSuffix(body, "\n\n"))
+
+	status := performRequest(handler.GetGenerationJob, http.MethodGet, "/records/jobs/:id", "/records/jobs/"+job.ID)
+	require.Equal(t, http.StatusOK, status.Code)
+	require.NoError(t, json.Unmarshal(status.Body.Bytes(), &job))
+	assert.Equal(t, services.JobCompleted, job.Status)
+	assert.Equal(t, 9000, job.Generated)
+
+	finished := performRequest(handler.CancelGenerationJob, http.MethodDelete, "/records/jobs/:id", "/records/jobs/"+job.ID)
+	assert.Equal(t, http.StatusConflict, finished.Code)
+	listed := performRequest(handler.GetRecords, http.MethodGet, "/records", "/records?limit=1")
+	assert.Contains(t, listed.Body.String(), `"total":9000`)
+
+	for _, path := range []string{"/records/jobs?count=0", "/records/jobs?count=1000001", "/records/jobs?profile=missing"} {
+		invalid := performRequest(handler.StartGenerationJob, http.MethodPost, "/records/jobs", path)
+		assert.Equal(t, http.StatusBadRequest, invalid.Code, path)
+	}
+	missing := performRequest(handler.GetGenerationJob, http.MethodGet, "/records/jobs/:id", "/records/jobs/missing")
+	assert.Equal(t, http.StatusNotFound, missing.Code)
+	missing = performRequest(handler.StreamGenerationJob, http.MethodGet, "/records/jobs/:id/events", "/records/jobs/missing/events")
+	assert.Equal(t, http.StatusNotFound, missing.Code)
+	missing = performRequest(handler.CancelGenerationJob, http.MethodDelete, "/records/jobs/:id", "/records/jobs/missing")
+	assert.Equal(t, http.StatusNotFound, missing.Code)
+}
+
+func TestRecordFeedStreamsFilteredChanges(t *testing.T) {
+	t.Parallel()
+	service := services.NewRecordService(repository.NewMemoryStore())
+	generated, err := service.RegenerateRecords(3, 8, "", "")
+	require.NoError(t, err)
+	records := append([]models.Record(nil), generated...)
+	router := gin.New()
+	RegisterFeedRoutes(router, NewRecordFeed(service, []string{"http://allowed.example"}))
+	server := httptest.NewServer(router)
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/api-go/ws/records"
+
+	_, response, err := websocket.DefaultDialer.Dial(wsURL, http.Header{"Origin": {"http://evil.example"}})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+	assert.Equal(t, problemContentType, response.Header.Get("Content-Type"))
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL+"?uid="+records[0].UID, http.Header{"Origin": {"http://allowed.example"}})
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(10*time.Second)))
+	read := func() map[string]any {
+		t.Helper()
+		var message map[string]any
+		require.NoError(t, conn.ReadJSON(&message))
+		return message
+	}
+	subscribed := read()
+	assert.Equal(t, "subscribed", subscribed["type"])
+	assert.Equal(t, map[string]any{"uids": []any{records[0].UID}}, subscribed["filter"])
+
+	// Only the watched record's change arrives.
+	require.NoError(t, service.DeleteRecord(records[1].UID))
+	require.NoError(t, service.DeleteRecord(records[0].UID))
+	deleted := read()
+	assert.Equal(t, "deleted", deleted["type"])
+	assert.Equal(t, records[0].UID, deleted["uid"])
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"action":"dance"}`)))
+	assert.Equal(t, "error", read()["type"])
+	require.NoError(t, conn.WriteJSON(FeedRequest{Action: "subscribe", ChangeFilter: repository.ChangeFilter{States: []string{records[2].Address.State}}}))
+	assert.Equal(t, "subscribed", read()["type"])
+
+	updated := records[2]
+	updated.FirstName = "Live"
+	_, err = service.UpdateRecord(updated.UID, updated)
+	require.NoError(t, err)
+	change := read()
+	assert.Equal(t, "updated", change["type"])
+	assert.Equal(t, "Live", change["record"].(map[string]any)["firstName"])
+
+	_, err = service.RegenerateRecords(2, 9, "", "")
+	require.NoError(t, err)
+	regenerated := read()
+	assert.Equal(t, "regenerated", regenerated["type"])
+	assert.Equal(t, float64(2), regenerated["count"])
+}
+
+func TestRecordGraphQLHandler(t *testing.T) {
+	t.Parallel()
+	handler := newTestRecordHandler(t, 12)
+	server, err := graphqlapi.New(handler.records, graphqlapi.DefaultMaxComplexity)
+	require.NoError(t, err)
+	graphql := NewRecordGraphQL(server)
+
+	response := performJSONRequest(graphql.Query, http.MethodPost, "/graphql", "/graphql",
+		`{"query":"query($n: Int) { records(first: $n) { totalCount nodes { firstName } } }","variables":{"n":5}}`)
+	require.Equal(t, http.StatusOK, response.Code)
+	var body struct {
+		Data struct {
+			Records struct {
+				TotalCount int
+				Nodes      []map[string]any
+			}
+		}
+		Errors []GraphQLError
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+	assert.Empty(t, body.Errors)
+	assert.Equal(t, 12, body.Data.Records.TotalCount)
+	require.Len(t, body.Data.Records.Nodes, 5)
+	assert.Len(t, body.Data.Records.Nodes[0], 1)
+
+	response = performRequest(graphql.Query, http.MethodGet, "/graphql", "/graphql?query="+url.QueryEscape("{ records(first: 1000000) { nodes { UID } } }"))
+	require.Equal(t, http.StatusOK, response.Code)
+	body.Errors = nil
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+	require.Len(t, body.Errors, 1)
+	assert.Contains(t, body.Errors[0].Message, "query complexity")
+
+	response = performJSONRequest(graphql.Query, http.MethodPost, "/graphql", "/graphql", `{"query":`)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	response = performRequest(graphql.Query, http.MethodGet, "/graphql", "/graphql?query=%7Bx%7D&variables=nope")
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	response = performRequest(graphql.Query, http.MethodGet, "/graphql", "/graphql")
+	assert.Equal(t, "Missing query", decodeProblem(t, response, http.StatusBadRequest).Detail)
+}
+
+func TestSparseFieldsets(t *testing.T) {
+	t.Parallel()
+	handler := newTestRecordHandler(t, 5)
+
+	response := performRequest(handler.GetRecords, http.MethodGet, "/records", "/records?pageSize=2&fields=UID,firstName,address.city")
+	require.Equal(t, http.StatusOK, response.Code)
+	var listing struct {
+		Records []map[string]any `json:"records"`
+		Total   int              `json:"total"`
+		Links   PageLinks        `json:"links"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &listing))
+	assert.Equal(t, 5, listing.Total, "the listing envelope is kept whole")
+	assert.NotEmpty(t, listing.Links.Next)
+	require.Len(t, listing.Records, 2)
+	for _, record := range listing.Records {
+		assert.Len(t, record, 3)
+		assert.Equal(t, []string{"city"}, mapKeys(record["address"].(map[string]any)))
+	}
+
+	uid := listing.Records[0]["UID"].(string)
+	response = performRequest(handler.GetRecordByUID, http.MethodGet, "/records/:UID", "/records/"+uid+"?fields=lastName,salary.companyName,address")
+	require.Equal(t, http.StatusOK, response.Code)
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &record))
+	assert.ElementsMatch(t, []string{"lastName", "salary", "address"}, mapKeys(record))
+	assert.Len(t, record["address"], 4, "a field selected whole keeps its subfields")
+	for _, company := range record["salary"].([]any) {
+		assert.Equal(t, []string{"companyName"}, mapKeys(company.(map[string]any)))
+	}
+
+	var all []string
+	for _, field := range jsonFields(reflect.TypeOf(models.Record{})) {
+		all = append(all, field.name)
+	}
+	full := performRequest(handler.GetRecordByUID, http.MethodGet, "/records/:UID", "/records/"+uid)
+	response = performRequest(handler.GetRecordByUID, http.MethodGet, "/records/:UID", "/records/"+uid+"?fields="+strings.Join(all, ","))
+	assert.Equal(t, full.Body.String(), response.Body.String(), "selecting every field matches the unprojected record")
+
+	response = performRequest(handler.GetRecordByUID, http.MethodGet, "/records/:UID", "/records/"+uid+"?version=1&fields=UID,salary.amount")
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Regexp(t, `^\{"UID":"[^"]+","salary":\[(\{"amount":[0-9.e+]+\},?)*\]\}$`, response.Body.String())
+
+	streamed := performRequest(handler.GetRecords, http.MethodGet, "/records", "/records?stream=ndjson&fields=UID")
+	require.Equal(t, http.StatusOK, streamed.Code)
+	lines := strings.Split(strings.TrimSpace(streamed.Body.String()), "\n")
+	require.Len(t, lines, 5)
+	assert.Regexp(t, `^\{"UID":"[^"]+"\}$`, lines[0])
+	streamed = performRequest(handler.GetRecords, http.MethodGet, "/records", "/records?stream=json&fields=firstName")
+	var streamedListing struct {
+		Records []map[string]any `json:"records"`
+		Total   int              `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(streamed.Body.Bytes(), &streamedListing))
+	assert.Equal(t, 5, streamedListing.Total)
+	assert.Equal(t, []string{"firstName"}, mapKeys(streamedListing.Records[0]))
+
+	for _, fields := range []string{"nope", "address.nope", "firstName.first", "UID,,lastName", "avatar.url"} {
+		response = performRequest(handler.GetRecords, http.MethodGet, "/records", "/records?fields="+fields)
+		assert.Equal(t, http.StatusBadRequest, response.Code, fields)
+		assert.Contains(t, response.Body.String(), "Invalid fields parameter", fields)
+		response = performRequest(handler.GetRecords, http.MethodGet, "/records", "/records?stream=ndjson&fields="+fields)
+		assert.Equal(t, http.StatusBadRequest, response.Code, fields)
+	}
+
+	response = performJSONRequest(handler.CreateRecord, http.MethodPost, "/records", "/records?fields=nope", `{"UID":"f-1","firstName":"Ann","lastName":"Lee"}`)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	_, err := handler.records.GetRecordByUID("f-1")
+	assert.ErrorIs(t, err, repository.ErrRecordNotFound, "invalid fields are rejected before the write")
+	response = performJSONRequest(handler.CreateRecord, http.MethodPost, "/records", "/records?fields=UID", `{"UID":"f-1","firstName":"Ann","lastName":"Lee"}`)
+	require.Equal(t, http.StatusCreated, response.Code)
+	assert.JSONEq(t, `{"UID":"f-1"}`, response.Body.String())
+
+	response = performRequest(handler.SearchRecords, http.MethodGet, "/records/search", "/records/search?q=ann&fields=firstName")
+	require.Equal(t, http.StatusOK, response.Code)
+	var search struct {
+		Hits []struct {
+			Record map[string]any `json:"record"`
+			Score  float64        `json:"score"`
+		} `json:"hits"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &search))
+	require.NotEmpty(t, search.Hits)
+	assert.Equal(t, map[string]any{"firstName": "Ann"}, search.Hits[0].Record)
+	assert.Positive(t, search.Hits[0].Score)
+}
+
+func TestFieldProjectionMatchesEncodingJSON(t *testing.T) {
+	t.Parallel()
+	type stamp struct {
+		ID    int64     `json:"id,string"`
+		At    time.Time `json:"at"`
+		Note  string    `json:"note,omitempty"`
+		Extra string    `json:"extra"`
+	}
+	value := struct {
+		Hits []struct {
+			Record stamp `json:"record"`
+			Score  int   `json:"score"`
+		} `json:"hits"`
+	}{}
+	value.Hits = append(value.Hits, struct {
+		Record stamp `json:"record"`
+		Score  int   `json:"score"`
+	}{Record: stamp{ID: 7, At: time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC), Extra: "<b>"}, Score: 3})
+
+	projected, err := fieldSelection{"id": nil, "at": nil, "note": nil}.project(value, "hits.record")
+	require.NoError(t, err)
+	assert.Equal(t, `{"hits":[{"record":{"id":"7","at":"2026-10-17T09:30:00Z"},"score":3}]}`, string(projected))
+
+	full, err := json.Marshal(value)
+	require.NoError(t, err)
+	projected, err = fieldSelection{"id": nil, "at": nil, "note": nil, "extra": nil}.project(value, "hits.record")
+	require.NoError(t, err)
+	assert.Equal(t, string(full), string(projected), "selecting every field keeps the encoding/json output")
+
+	assert.Error(t, fieldSelection{"at": {"year": nil}}.check(reflect.TypeFor[stamp]()), "time.Time encodes itself")
+}
+
+func mapKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestProblemMiddleware(t *testing.T) {
+	t.Parallel()
+	handler := newTestRecordHandler(t, 1)
+	router := gin.New()
+	router.Use(Problems())
+	router.NoRoute(RouteNotFound)
+	router.GET("/records/:UID", handler.GetRecordByUID)
+	router.GET("/broken", func(c *gin.Context) { writeRecordError(c, errors.New("disk on fire")) })
+	router.GET("/busy", func(c *gin.Context) { abortWithProblem(c, services.ErrTooManyJobs) })
+	router.GET("/odd", func(c *gin.Context) { abortWithProblem(c, repository.NewError("mystery", "odd failure")) })
+	router.GET("/records", handler.GetRecords)
+	serve := func(path, correlationID string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, path, nil)
+		if correlationID != "" {
+			request.Header.Set(CorrelationHeader, correlationID)
+		}
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		return response
+	}
+
+	missing := serve("/records/missing", "trace-42")
+	problem := decodeProblem(t, missing, http.StatusNotFound)
+	assert.Equal(t, ErrorResponse{
+		Type:          "https://jeffreysanford.us/problems/not-found",
+		Title:         "Resource not found",
+		Status:        http.StatusNotFound,
+		Detail:        "Record not found",
+		Instance:      "/records/missing",
+		CorrelationID: "trace-42",
+	}, problem)
+
+	unsafe := serve("/records/missing", "two words")
+	assert.NotEqual(t, "two words", decodeProblem(t, unsafe, http.StatusNotFound).CorrelationID)
+
+	generated := serve("/records/missing", "")
+	assert.Len(t, generated.Header().Get(CorrelationHeader), 32)
+
+	broken := decodeProblem(t, serve("/broken", ""), http.StatusInternalServerError)
+	assert.Equal(t, "Record operation failed", broken.Detail)
+	assert.NotContains(t, broken.Detail, "disk", "unexpected errors are not shown to clients")
+
+	unknown := decodeProblem(t, serve("/nowhere", ""), http.StatusNotFound)
+	assert.Equal(t, "No route matches /nowhere", unknown.Detail)
+
+	invalid := decodeProblem(t, serve("/records/missing?version=3", ""), http.StatusBadRequest)
+	assert.Equal(t, []repository.FieldError{{Field: "version", Message: "Invalid version parameter"}}, invalid.Errors)
+
+	busy := serve("/busy", "")
+	assert.Equal(t, "https://jeffreysanford.us/problems/unavailable", decodeProblem(t, busy, http.StatusServiceUnavailable).Type)
+	assert.Equal(t, retryAfterSeconds, busy.Header().Get("Retry-After"))
+
+	odd := decodeProblem(t, serve("/odd", ""), http.StatusInternalServerError)
+	assert.Equal(t, "The request could not be completed", odd.Detail, "unmapped kinds fall back to internal")
+
+	for _, path := range []string{"/records?limit=1000001", "/records?pageSize=1000001"} {
+		tooMany := decodeProblem(t, serve(path, ""), http.StatusBadRequest)
+		assert.Equal(t, "https://jeffreysanford.us/problems/limit-exceeded", tooMany.Type, path)
+	}
+}
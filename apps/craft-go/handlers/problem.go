@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"craft-fusion/craft-go/repository"
+	"craft-fusion/craft-go/services"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// problemContentType is the media type of RFC 7807 error bodies.
+const problemContentType = "application/problem+json"
+
+// problemTypeBase prefixes the type URI of every problem.
+const problemTypeBase = "https://jeffreysanford.us/problems/"
+
+// CorrelationHeader carries the correlation ID of a request. A client may
+// send its own; otherwise one is generated. Either way it is echoed in the
+// response and in every problem body.
+const CorrelationHeader = "X-Request-ID"
+
+// correlationKey stores the correlation ID in the gin context.
+const correlationKey = "correlationID"
+
+// maxCorrelationIDLength bounds the client-supplied IDs that are reused.
+const maxCorrelationIDLength = 128
+
+// retryAfterSeconds is the Retry-After hint sent with unavailable problems.
+const retryAfterSeconds = "10"
+
+// problemType is a kind of problem: its type URI suffix, status and title.
+type problemType struct {
+	name   string
+	status int
+	title  string
+}
+
+var (
+	problemBadRequest     = problemType{"bad-request", http.StatusBadRequest, "Bad request"}
+	problemNotFound       = problemType{"not-found", http.StatusNotFound, "Resource not found"}
+	problemConflict       = problemType{"conflict", http.StatusConflict, "Conflict with the current state"}
+	problemValidation     = problemType{"validation", http.StatusUnprocessableEntity, "Validation failed"}
+	problemLimitExceeded  = problemType{"limit-exceeded", http.StatusBadRequest, "Limit exceeded"}
+	problemUnavailable    = problemType{"unavailable", http.StatusServiceUnavailable, "Service unavailable"}
+	problemInternal       = problemType{"internal", http.StatusInternalServerError, "Internal server error"}
+	problemNotImplemented = problemType{"not-implemented", http.StatusNotImplemented, "Not implemented"}
+)
+
+// domainProblems maps each domain error kind to the problem it answers.
+var domainProblems = map[repository.Kind]problemType{
+	repository.KindNotFound:      problemNotFound,
+	repository.KindValidation:    problemValidation,
+	repository.KindConflict:      problemConflict,
+	repository.KindLimitExceeded: problemLimitExceeded,
+	repository.KindUnavailable:   problemUnavailable,
+}
+
+// requestError is a failure a handler detects itself, such as a malformed
+// parameter, rather than one returned by the record service.
+type requestError struct {
+	problem problemType
+	detail  string
+	// fields lists the offending parameters, if any.
+	fields []repository.FieldError
+	// err is the cause, logged but never shown to clients.
+	err error
+}
+
+func (e *requestError) Error() string {
+	return e.detail
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+// invalidParameter reports a query parameter the handler cannot accept.
+func invalidParameter(name string) error {
+	return parameterError(name, fmt.Sprintf("Invalid %s parameter", name))
+}
+
+// parameterError reports a query parameter the handler cannot accept, with
+// detail explaining why.
+func parameterError(name, detail string) error {
+	return &requestError{problem: problemBadRequest, detail: detail, fields: []repository.FieldError{{Field: name, Message: detail}}}
+}
+
+// limitError reports a query parameter asking for more than the handler
+// serves, such as a count over 1,000,000.
+func limitError(name, detail string) error {
+	return &requestError{problem: problemLimitExceeded, detail: detail, fields: []repository.FieldError{{Field: name, Message: detail}}}
+}
+
+// asBadRequest answers a validation error caused by the query parameters,
+// such as an unknown sort field, or by an unreadable body as a bad request;
+// 422 is kept for well-formed bodies that break a rule.
+func asBadRequest(err error) error {
+	var domainErr *repository.Error
+	if !errors.As(err, &domainErr) || domainErr.Kind != repository.KindValidation {
+		return err
+	}
+	return &requestError{problem: problemBadRequest, detail: sentence(err.Error()), fields: domainErr.Fields, err: err}
+}
+
+// badRequest reports a request body or parameters that cannot be read.
+func badRequest(detail string, err error) error {
+	return &requestError{problem: problemBadRequest, detail: detail, err: err}
+}
+
+// internalError reports an unexpected failure with a detail safe to show.
+func internalError(detail string, err error) error {
+	return &requestError{problem: problemInternal, detail: detail, err: err}
+}
+
+// RouteNotFound answers requests for unknown routes with a not-found problem.
+func RouteNotFound(c *gin.Context) {
+	abortWithProblem(c, &requestError{problem: problemNotFound, detail: "No route matches " + c.Request.URL.Path})
+}
+
+// serviceError passes domain errors through and hides any other error behind
+// an internal problem with detail.
+func serviceError(err error, detail string) error {
+	if _, ok := repository.KindOf(err); ok {
+		return err
+	}
+	return internalError(detail, err)
+}
+
+// Problems assigns every request a correlation ID and answers the error a
+// handler raised with abortWithProblem as an RFC 7807 problem. Install it
+// before the routes.
+func Problems() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := correlationID(c.GetHeader(CorrelationHeader))
+		c.Set(correlationKey, id)
+		c.Header(CorrelationHeader, id)
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		writeProblem(c, c.Errors.Last().Err)
+	}
+}
+
+// abortWithProblem stops the handler chain with err, which Problems renders.
+// Without the middleware, as in a bare test router, the problem is written
+// at once.
+func abortWithProblem(c *gin.Context, err error) {
+	c.Abort()
+	if _, ok := c.Get(correlationKey); !ok {
+		writeProblem(c, err)
+		return
+	}
+	_ = c.Error(err)
+}
+
+// writeProblem writes the problem err describes.
+func writeProblem(c *gin.Context, err error) {
+	problem := problemFor(err)
+	problem.Instance = c.Request.URL.Path
+	problem.CorrelationID = c.GetString(correlationKey)
+	if problem.CorrelationID == "" {
+		problem.CorrelationID = correlationID(c.GetHeader(CorrelationHeader))
+		c.Header(CorrelationHeader, problem.CorrelationID)
+	}
+	if problem.Status == http.StatusServiceUnavailable {
+		c.Header("Retry-After", retryAfterSeconds)
+	}
+	if problem.Status >= http.StatusInternalServerError && problem.Status != http.StatusServiceUnavailable {
+		log.Printf("%s %s failed [%s]: %v", c.Request.Method, problem.Instance, problem.CorrelationID, err)
+	}
+	c.Header("Content-Type", problemContentType)
+	c.JSON(problem.Status, problem)
+}
+
+// problemFor describes err as a problem. Errors the handlers and the record
+// service do not know, including domain errors of an unmapped kind, are
+// reported as internal without their message.
+func problemFor(err error) ErrorResponse {
+	var requestErr *requestError
+	var domainErr *repository.Error
+	var validationErrs validator.ValidationErrors
+	switch {
+	case errors.As(err, &requestErr):
+		problem := requestErr.problem.response(requestErr.detail)
+		problem.Errors = requestErr.fields
+		return problem
+	case errors.As(err, &domainErr):
+		known, ok := domainProblems[domainErr.Kind]
+		if !ok {
+			return problemInternal.response("The request could not be completed")
+		}
+		problem := known.response(sentence(err.Error()))
+		problem.Errors = domainErr.Fields
+		return problem
+	case errors.As(err, &validationErrs):
+		return problemFor(services.NewValidationError(validationErrs))
+	default:
+		return problemInternal.response("The request could not be completed")
+	}
+}
+
+func (p problemType) response(detail string) ErrorResponse {
+	return ErrorResponse{Type: problemTypeBase + p.name, Title: p.title, Status: p.status, Detail: detail}
+}
+
+// sentence capitalizes a Go error message for display.
+func sentence(message string) string {
+	r, size := utf8.DecodeRuneInString(message)
+	if size == 0 {
+		return message
+	}
+	return string(unicode.ToUpper(r)) + message[size:]
+}
+
+// correlationID reuses a client-supplied ID made of printable ASCII, or
+// generates a new one.
+func correlationID(supplied string) string {
+	if supplied != "" && len(supplied) <= maxCorrelationIDLength && strings.IndexFunc(supplied, func(r rune) bool {
+		return r <= ' ' || r > '~'
+	}) < 0 {
+		return supplied
+	}
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// isMalformedJSON reports whether err comes from a body that is not JSON of
+// the expected shape.
+func isMalformedJSON(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"craft-fusion/craft-go/services"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AggregateRecords serves grouped metrics over the stored dataset.
+// @Summary Aggregate records
+// @Description Groups the stored dataset and computes metrics per group, largest groups first. Grouping by `companyName` or `companyPosition` aggregates one row per employer entry, so `groupBy=companyName&limit=10` lists the top employers. Results are cached until the dataset changes.
+// @Tags Records
+// @Produce json
+// @Param groupBy query string false "Comma-separated group fields: state, city, zipcode, lastName, companyName, companyPosition; omit for one group over the whole dataset" example(state)
+// @Param metrics query string false "Comma-separated metrics: count, sum/avg/min/max or pNN followed by Income or Salary, salaryHistogram" example(count,avgIncome,p90Income)
+// @Param limit query int false "Keep only the largest N groups; 0 keeps all" default(0)
+// @Param bucketSize query number false "Salary histogram bucket width" default(10000)
+// @Success 200 {object} services.AggregateResult
+// @Failure 400 {object} ErrorResponse
+// @Router /api-go/records/aggregate [get]
+func (h *RecordHandler) AggregateRecords(c *gin.Context) {
+	query := services.AggregateQuery{
+		GroupBy: listQuery(c, "groupBy"),
+		Metrics: listQuery(c, "metrics"),
+	}
+
+	var err error
+	if query.Limit, err = intQuery(c, "limit"); err != nil {
+		abortWithProblem(c, err)
+		return
+	}
+	bucketSize, err := floatQuery(c, "bucketSize")
+	if err != nil {
+		abortWithProblem(c, err)
+		return
+	}
+	if bucketSize != nil {
+		query.BucketSize = *bucketSize
+	}
+
+	result, err := h.records.Aggregate(query)
+	if err != nil {
+		abortWithProblem(c, asBadRequest(err))
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// listQuery splits a comma-separated query parameter, dropping empty items.
+func listQuery(c *gin.Context, name string) []string {
+	var values []string
+	for _, value := range strings.Split(c.Query(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
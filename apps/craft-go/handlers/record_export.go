@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"craft-fusion/craft-go/models"
+	"craft-fusion/craft-go/recordio"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportRecords downloads the matching records as a CSV, NDJSON or XLSX file.
+// @Summary Export records
+// @Description Streams the records matching the list filters as a file download, as they are read from the store. CSV and XLSX flatten address, phone and salary into columns such as `address.city` and `salary[0].companyName`, with one set of salary columns per company slot; NDJSON keeps the nested record shape. See the recordio package for the full column mapping.
+// @Tags Records
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "File format" Enums(csv, ndjson, xlsx) default(csv)
+// @Param limit query int false "Maximum number of records to export when pageSize is not set (1-1000000); omit to export every match"
+// @Param page query int false "1-based page number" default(1)
+// @Param pageSize query int false "Records per page; 0 exports all matches" default(0)
+// @Param sort query string false "Comma-separated sort fields, prefix with - for descending" example(lastName,-totalHouseholdIncome)
+// @Param state query string false "Exact state match, case-insensitive"
+// @Param city query string false "Exact city match, case-insensitive"
+// @Param lastName query string false "Exact last name match, case-insensitive"
+// @Param zipcode query string false "Exact zipcode match"
+// @Param minIncome query number false "Minimum total household income"
+// @Param maxIncome query number false "Maximum total household income"
+// @Param q query string false "Case-insensitive substring match on name, address and email"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Router /api-go/records/export [get]
+func (h *RecordHandler) ExportRecords(c *gin.Context) {
+	format := c.DefaultQuery("format", recordio.FormatCSV)
+	contentType := recordio.ContentType(format)
+	if contentType == "" {
+		abortWithProblem(c, invalidParameter("format"))
+		return
+	}
+	limit, err := limitQuery(c, 0)
+	if err != nil {
+		abortWithProblem(c, err)
+		return
+	}
+	query, err := parseRecordQuery(c)
+	if err != nil {
+		abortWithProblem(c, err)
+		return
+	}
+	query.Limit = limit
+
+	// A flat file needs its header, and so its company slots, before the
+	// first row: find them in a first pass that keeps no records.
+	layout := recordio.NewLayout(0)
+	if format != recordio.FormatNDJSON {
+		slots := 0
+		_, err := h.records.EachRecord(query, func(record models.Record) error {
+			slots = max(slots, len(record.Salary))
+			return nil
+		})
+		if err != nil {
+			abortWithProblem(c, asBadRequest(err))
+			return
+		}
+		layout = recordio.NewLayout(slots)
+	}
+
+	filename := fmt.Sprintf("records-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+	var stream *recordStream
+	var writer recordio.Writer
+	open := func() (err error) {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		stream = newRecordStream(c, contentType)
+		writer, err = recordio.NewWriter(c.Writer, format, layout)
+		return err
+	}
+	written := 0
+	// Rows are written as the second pass visits them. A record gaining
+	// companies between the passes no longer fits the header and ends the
+	// download early with ErrLayoutTooNarrow instead of losing columns.
+	_, err = h.records.EachRecord(query, func(record models.Record) error {
+		if stream == nil {
+			if err := open(); err != nil {
+				return err
+			}
+		}
+		if err := c.Request.Context().Err(); err != nil {
+			return err
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+		written++
+		if written%streamFlushInterval == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			stream.flush()
+		}
+		return nil
+	})
+	switch {
+	case err != nil && stream == nil:
+		abortWithProblem(c, asBadRequest(err))
+		return
+	case err != nil:
+		return
+	case stream == nil:
+		if open() != nil {
+			return
+		}
+	}
+	if writer.Close() == nil {
+		stream.flush()
+	}
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"craft-fusion/craft-go/repository"
+	"craft-fusion/craft-go/services"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// feedBufferSize is how many changes a slow client may fall behind
+	// before changes are dropped and it receives a dropped notice.
+	feedBufferSize = 256
+	// feedWriteWait bounds each message write.
+	feedWriteWait = 10 * time.Second
+	// feedPongWait is how long the connection may stay silent; pings are
+	// sent often enough that a live client always answers in time.
+	feedPongWait   = 60 * time.Second
+	feedPingPeriod = feedPongWait * 9 / 10
+	// feedMaxRequestSize bounds a client subscription message.
+	feedMaxRequestSize = 64 << 10
+)
+
+// Feed notice types sent alongside changes.
+const (
+	feedSubscribed = "subscribed"
+	feedDropped    = "dropped"
+	feedError      = "error"
+)
+
+// FeedNotice is a message on the record change feed that is not a change:
+// the filter now in effect after connecting or subscribing, the number of
+// changes dropped because the client read too slowly, or a rejected request.
+type FeedNotice struct {
+	Type    string                   `json:"type" example:"dropped" enums:"subscribed,dropped,error"`
+	Filter  *repository.ChangeFilter `json:"filter,omitempty"`
+	Dropped uint64                   `json:"dropped,omitempty" example:"12"`
+	Error   string                   `json:"error,omitempty"`
+}
+
+// FeedRequest is a client message on the record change feed. The only action
+// is subscribe, which replaces the filter; send empty lists to receive every
+// change.
+type FeedRequest struct {
+	Action string `json:"action" example:"subscribe"`
+	repository.ChangeFilter
+}
+
+// RecordFeed serves the WebSocket change feed of the record dataset.
+type RecordFeed struct {
+	records  *services.RecordService
+	upgrader websocket.Upgrader
+}
+
+// NewRecordFeed returns a RecordFeed for records that accepts connections
+// from the same host and from allowedOrigins.
+func NewRecordFeed(records *services.RecordService, allowedOrigins []string) *RecordFeed {
+	return &RecordFeed{
+		records: records,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+				if origin == "" || slices.Contains(allowedOrigins, origin) {
+					return true
+				}
+				parsed, err := url.Parse(origin)
+				return err == nil && parsed.Host == r.Host
+			},
+			Error: upgradeError,
+		},
+	}
+}
+
+// RegisterFeedRoutes mounts the change feed at /api-go/ws/records.
+func RegisterFeedRoutes(router gin.IRouter, feed *RecordFeed) {
+	router.GET("/api-go/ws/records", feed.ServeRecords)
+}
+
+// ServeRecords streams record changes over a WebSocket.
+// @Summary Record change feed
+// @Description WebSocket. Each text message is a JSON change (`created`, `updated`, `deleted`, `regenerated` or `merged`) or a notice (`subscribed`, `dropped`, `error`). Narrow the feed with `state` and `uid` query parameters or by sending `{"action":"subscribe","states":[...],"uids":[...]}`. A client that falls 256 changes behind loses changes and receives a `dropped` notice with their count; refetch the records it watches.
+// @Tags Records
+// @Param state query []string false "Only changes to records in these states" collectionFormat(multi)
+// @Param uid query []string false "Only changes to these records" collectionFormat(multi)
+// @Success 101 {object} repository.Change
+// @Failure 400 {object} ErrorResponse
+// @Router /api-go/ws/records [get]
+func (f *RecordFeed) ServeRecords(c *gin.Context) {
+	filter := repository.ChangeFilter{States: c.QueryArray("state"), UIDs: c.QueryArray("uid")}
+	conn, err := f.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already answered with a problem.
+		return
+	}
+	defer conn.Close()
+
+	subscription := f.records.SubscribeChanges(filter, feedBufferSize)
+	defer subscription.Close()
+
+	notices := make(chan FeedNotice)
+	done := make(chan struct{})
+	defer close(done)
+	go readFeedRequests(conn, subscription, notices, done)
+	writeFeed(conn, subscription, notices)
+}
+
+// upgradeError answers a failed WebSocket handshake with a problem named by
+// its status, as RFC 7807 suggests for problems without a type of their own.
+func upgradeError(w http.ResponseWriter, r *http.Request, status int, reason error) {
+	w.Header().Set("Sec-Websocket-Version", "13")
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(ErrorResponse{
+		Type:          "about:blank",
+		Title:         http.StatusText(status),
+		Status:        status,
+		Detail:        sentence(reason.Error()),
+		Instance:      r.URL.Path,
+		CorrelationID: w.Header().Get(CorrelationHeader),
+	})
+}
+
+// readFeedRequests applies subscription requests until the connection fails,
+// then closes notices. Notices go to the writer, the only goroutine allowed
+// to write to conn.
+func readFeedRequests(conn *websocket.Conn, subscription *repository.Subscription, notices chan<- FeedNotice, done <-chan struct{}) {
+	defer close(notices)
+	conn.SetReadLimit(feedMaxRequestSize)
+	_ = conn.SetReadDeadline(time.Now().Add(feedPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(feedPongWait))
+	})
+
+	for {
+		var request FeedRequest
+		err := conn.ReadJSON(&request)
+		if err != nil && !isJSONError(err) {
+			return
+		}
+		notice := FeedNotice{Type: feedError, Error: "Invalid feed request"}
+		if err == nil && request.Action == "subscribe" {
+			subscription.SetFilter(request.ChangeFilter)
+			notice = FeedNotice{Type: feedSubscribed, Filter: &request.ChangeFilter}
+		}
+		select {
+		case notices <- notice:
+		case <-done:
+			return
+		}
+	}
+}
+
+// writeFeed sends the current filter, then changes, notices and pings until
+// the reader stops or a write fails.
+func writeFeed(conn *websocket.Conn, subscription *repository.Subscription, notices <-chan FeedNotice) {
+	ping := time.NewTicker(feedPingPeriod)
+	defer ping.Stop()
+
+	filter := subscription.Filter()
+	if writeFeedMessage(conn, FeedNotice{Type: feedSubscribed, Filter: &filter}) != nil {
+		return
+	}
+	for {
+		var err error
+		select {
+		case change := <-subscription.Changes():
+			err = writeFeedMessage(conn, change)
+		case <-subscription.Dropped():
+			if dropped := subscription.TakeDropped(); dropped > 0 {
+				err = writeFeedMessage(conn, FeedNotice{Type: feedDropped, Dropped: dropped})
+			}
+		case notice, ok := <-notices:
+			if !ok {
+				return
+			}
+			err = writeFeedMessage(conn, notice)
+		case <-ping.C:
+			err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(feedWriteWait))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// isJSONError reports whether err is a malformed message rather than a
+// failed connection.
+func isJSONError(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func writeFeedMessage(conn *websocket.Conn, message any) error {
+	_ = conn.SetWriteDeadline(time.Now().Add(feedWriteWait))
+	return conn.WriteJSON(message)
+}
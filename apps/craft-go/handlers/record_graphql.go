@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"craft-fusion/craft-go/graphqlapi"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GraphQLResponse describes a GraphQL result: data, errors, or both when
+// only some fields failed.
+type GraphQLResponse struct {
+	Data   any            `json:"data"`
+	Errors []GraphQLError `json:"errors,omitempty"`
+}
+
+// GraphQLError describes one GraphQL error.
+type GraphQLError struct {
+	Message string `json:"message" example:"query complexity 250300 exceeds the limit of 100000; request fewer records or fields"`
+}
+
+// RecordGraphQL serves the GraphQL API of the record dataset.
+type RecordGraphQL struct {
+	server *graphqlapi.Server
+}
+
+// NewRecordGraphQL returns a RecordGraphQL that executes requests with server.
+func NewRecordGraphQL(server *graphqlapi.Server) *RecordGraphQL {
+	return &RecordGraphQL{server: server}
+}
+
+// RegisterGraphQLRoutes mounts the GraphQL endpoint at /api-go/graphql.
+func RegisterGraphQLRoutes(router gin.IRouter, graphql *RecordGraphQL) {
+	router.GET("/api-go/graphql", graphql.Query)
+	router.POST("/api-go/graphql", graphql.Query)
+}
+
+// Query executes a GraphQL request.
+// @Summary Query records with GraphQL
+// @Description Selects only the record fields a client needs. `records(first, after, sort, state, city, lastName, zipcode, q, minIncome, maxIncome)` returns a connection with `totalCount`, `edges { cursor node }`, `nodes` and `pageInfo`; `record(UID)` returns one record. Record, Address, Phone and Company fields use the JSON names of the REST API. Operations are rejected before execution when the requested records times the selected fields exceeds the complexity limit. Errors are reported in the `errors` array with status 200; GET accepts `query`, `operationName` and JSON-encoded `variables` parameters.
+// @Tags Records
+// @Accept json
+// @Produce json
+// @Param request body graphqlapi.Request true "GraphQL request"
+// @Success 200 {object} GraphQLResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /api-go/graphql [post]
+func (g *RecordGraphQL) Query(c *gin.Context) {
+	var request graphqlapi.Request
+	if c.Request.Method == http.MethodGet {
+		request.Query, request.OperationName = c.Query("query"), c.Query("operationName")
+		if raw := c.Query("variables"); raw != "" && json.Unmarshal([]byte(raw), &request.Variables) != nil {
+			abortWithProblem(c, invalidParameter("variables"))
+			return
+		}
+	} else if err := c.ShouldBindJSON(&request); err != nil {
+		abortWithProblem(c, badRequest("Invalid GraphQL request body", err))
+		return
+	}
+	if request.Query == "" {
+		abortWithProblem(c, parameterError("query", "Missing query"))
+		return
+	}
+	c.JSON(http.StatusOK, g.server.Execute(c.Request.Context(), request))
+}
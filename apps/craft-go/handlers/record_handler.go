@@ -1,58 +1,371 @@
 package handlers
 
 import (
+	"bytes"
+	"craft-fusion/craft-go/models"
 	"craft-fusion/craft-go/services"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 )
 
-// GetRecordsHandler serves user records based on limit.
+// RecordHandler serves the record endpoints from an injected RecordService.
+type RecordHandler struct {
+	records *services.RecordService
+}
+
+// NewRecordHandler returns a RecordHandler backed by the given service.
+func NewRecordHandler(records *services.RecordService) *RecordHandler {
+	return &RecordHandler{records: records}
+}
+
+// GetRecords serves a filtered, sorted page of the stored user records.
 // @Summary List records
-// @Description Returns a generated list of records. Use the `limit` query parameter to control count.
+// @Description Filters, sorts and pages the stored dataset without modifying it. Without `pageSize` up to `limit` matching records are returned. Use POST /api-go/records/seed to regenerate the dataset.
 // @Tags Records
 // @Produce json
-// @Param limit query int false "Maximum number of records to return (1-1000000)" default(1000)
+// @Produce application/x-ndjson
+// @Param limit query int false "Maximum number of records to return when pageSize is not set (1-1000000)" default(1000)
+// @Param page query int false "1-based page number" default(1)
+// @Param pageSize query int false "Records per page (0-1000000); 0 returns all matches" default(0)
+// @Param sort query string false "Comma-separated sort fields, prefix with - for descending" example(lastName,-totalHouseholdIncome)
+// @Param state query string false "Exact state match, case-insensitive"
+// @Param city query string false "Exact city match, case-insensitive"
+// @Param lastName query string false "Exact last name match, case-insensitive"
+// @Param zipcode query string false "Exact zipcode match"
+// @Param minIncome query number false "Minimum total household income"
+// @Param maxIncome query number false "Maximum total household income"
+// @Param q query string false "Case-insensitive substring match on name, address and email"
+// @Param stream query string false "Stream the response: json keeps the RecordsResponse shape, ndjson writes one record per line (also selected by Accept: application/x-ndjson)" Enums(json, ndjson)
+// @Param version query string false "Record representation: 2 (canonical Record) or 1 (legacy UserRecord); also read from X-Record-Version" Enums(1, 2) default(2)
+// @Param fields query string false "Comma-separated record fields to return, with dots for nested fields; omit for every field" example(UID,firstName,address.city)
 // @Success 200 {object} RecordsResponse
 // @Failure 400 {object} ErrorResponse
 // @Router /api-go/records [get]
 // @Router /api/records [get]
-func GetRecordsHandler(c *gin.Context) {
-	limitParam := c.DefaultQuery("limit", "1000")
-	limit, err := strconv.Atoi(limitParam)
-	if err != nil || limit <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+func (h *RecordHandler) GetRecords(c *gin.Context) {
+	limit, err := limitQuery(c, 1000)
+	if err != nil {
+		abortWithProblem(c, err)
+		return
+	}
+	query, err := parseRecordQuery(c)
+	if err != nil {
+		abortWithProblem(c, err)
+		return
+	}
+	version, err := recordVersion(c)
+	if err != nil {
+		abortWithProblem(c, err)
+		return
+	}
+
+	query.Limit = limit
+	if mode := recordStreamMode(c); mode != "" {
+		streamRecordsResponse(c, h.records, query, mode, version)
+		return
+	}
+	page, err := h.records.QueryRecords(query)
+	if err != nil {
+		abortWithProblem(c, asBadRequest(err))
 		return
 	}
+	renderRecordsResponse(c, RecordsResponse{
+		Records: page.Records,
+		PageInfo: PageInfo{
+			Total:    page.Total,
+			Page:     page.Page,
+			PageSize: page.PageSize,
+			Links:    pageLinks(c.Request.URL, page),
+		},
+	}, version)
+}
 
-	if limit > 1000000 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Limit cannot exceed 1,000,000 records"})
+// SeedRecords replaces the stored dataset with freshly generated records.
+// @Summary Regenerate dataset
+// @Description Replaces the stored dataset with `count` generated records. Existing UIDs become invalid.
+// @Tags Records
+// @Produce json
+// @Param count query int false "Number of records to generate (1-1000000)" default(1000)
+// @Param seed query int false "Generator seed; omit for a random seed reported in the response"
+// @Param profile query string false "Generator profile, see GET /api-go/records/profiles; omit for the server default"
+// @Param locale query string false "Locale of names, addresses, phones and salary currency, such as de-DE; see GET /api-go/records/profiles"
+// @Success 200 {object} SeedResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /api-go/records/seed [post]
+func (h *RecordHandler) SeedRecords(c *gin.Context) {
+	count, err := strconv.Atoi(c.DefaultQuery("count", "1000"))
+	if err != nil || count <= 0 {
+		abortWithProblem(c, invalidParameter("count"))
+		return
+	}
+	if count > 1000000 {
+		abortWithProblem(c, limitError("count", "Count cannot exceed 1,000,000 records"))
 		return
 	}
 
-	records := services.GetRecords(limit)
-	c.JSON(http.StatusOK, gin.H{
-		"records": records,
-	})
+	seed, err := seedQuery(c)
+	if err != nil {
+		abortWithProblem(c, err)
+		return
+	}
+
+	records, err := h.records.RegenerateRecords(count, seed, c.Query("profile"), c.Query("locale"))
+	if err != nil {
+		abortWithProblem(c, asBadRequest(serviceError(err, "Failed to store records")))
+		return
+	}
+	c.Header(seedHeader, strconv.FormatInt(seed, 10))
+	c.JSON(http.StatusOK, SeedResponse{Count: len(records), Seed: seed})
 }
 
-// GetRecordByUIDHandler serves a user record based on UID.
+// GetRecordByUID serves a user record based on UID.
 // @Summary Get record by UID
 // @Description Returns one record matching the provided UID.
 // @Tags Records
 // @Produce json
 // @Param UID path string true "Record UID"
-// @Success 200 {object} models.UserRecord
+// @Param version query string false "Record representation: 2 (canonical Record) or 1 (legacy UserRecord); also read from X-Record-Version" Enums(1, 2) default(2)
+// @Param fields query string false "Comma-separated record fields to return, with dots for nested fields; omit for every field" example(UID,firstName,address.city)
+// @Success 200 {object} models.Record
+// @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Router /api-go/records/{UID} [get]
 // @Router /api/records/{UID} [get]
-func GetRecordByUIDHandler(c *gin.Context) {
+func (h *RecordHandler) GetRecordByUID(c *gin.Context) {
 	uid := c.Param("UID")
-	record, err := services.GetRecordByUID(uid)
+	version, err := recordVersion(c)
+	if err != nil {
+		abortWithProblem(c, err)
+		return
+	}
+	record, err := h.records.GetRecordByUID(uid)
+	if err != nil {
+		writeRecordError(c, err)
+		return
+	}
+	renderRecord(c, http.StatusOK, record, version)
+}
+
+// GetTotalIncome serves the summed company salaries of one record.
+// @Summary Get total income
+// @Description Returns the sum of `annualSalary` across the record's companies as a bare JSON number, matching the NestJS backend. Unknown UIDs return 404 where NestJS returns 500.
+// @Tags Records
+// @Produce json
+// @Param UID path string true "Record UID"
+// @Success 200 {number} number
+// @Failure 404 {object} ErrorResponse
+// @Router /api-go/records/total-income/{UID} [get]
+// @Router /api/records/total-income/{UID} [get]
+func (h *RecordHandler) GetTotalIncome(c *gin.Context) {
+	total, err := h.records.TotalIncome(c.Param("UID"))
+	if err != nil {
+		writeRecordError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, total)
+}
+
+// CreateRecord stores a new record.
+// @Summary Create record
+// @Description Stores a new record. A UID is assigned when the body omits one.
+// @Tags Records
+// @Accept json
+// @Produce json
+// @Param record body models.Record true "Record to create"
+// @Param fields query string false "Comma-separated record fields to return, with dots for nested fields; omit for every field" example(UID,firstName,address.city)
+// @Success 201 {object} models.Record
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /api-go/records [post]
+func (h *RecordHandler) CreateRecord(c *gin.Context) {
+	if _, ok := requestedFieldsOf[models.Record](c); !ok {
+		return
+	}
+	var record models.Record
+	if err := c.ShouldBindJSON(&record); err != nil {
+		writeRecordError(c, err)
+		return
+	}
+
+	created, err := h.records.CreateRecord(record)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+		writeRecordError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, record)
+	c.Header("Location", c.Request.URL.Path+"/"+created.UID)
+	writeRecordJSON[models.Record](c, http.StatusCreated, created, "")
+}
+
+// UpdateRecord replaces an existing record.
+// @Summary Replace record
+// @Description Replaces the record matching the provided UID with the request body.
+// @Tags Records
+// @Accept json
+// @Produce json
+// @Param UID path string true "Record UID"
+// @Param record body models.Record true "Replacement record"
+// @Param fields query string false "Comma-separated record fields to return, with dots for nested fields; omit for every field" example(UID,firstName,address.city)
+// @Success 200 {object} models.Record
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /api-go/records/{UID} [put]
+func (h *RecordHandler) UpdateRecord(c *gin.Context) {
+	if _, ok := requestedFieldsOf[models.Record](c); !ok {
+		return
+	}
+	uid := c.Param("UID")
+	var record models.Record
+	if err := c.ShouldBindJSON(&record); err != nil {
+		writeRecordError(c, err)
+		return
+	}
+	if record.UID != "" && record.UID != uid {
+		writeRecordError(c, errUIDMismatch)
+		return
+	}
+
+	updated, err := h.records.UpdateRecord(uid, record)
+	if err != nil {
+		writeRecordError(c, err)
+		return
+	}
+	writeRecordJSON[models.Record](c, http.StatusOK, updated, "")
+}
+
+// PatchRecord merges the request body into an existing record.
+// @Summary Patch record
+// @Description Merges the JSON body into the record matching the provided UID as a JSON merge patch (RFC 7396): objects are merged field by field, arrays are replaced whole and null clears a field.
+// @Tags Records
+// @Accept json
+// @Produce json
+// @Param UID path string true "Record UID"
+// @Param record body models.Record true "Fields to change"
+// @Param fields query string false "Comma-separated record fields to return, with dots for nested fields; omit for every field" example(UID,firstName,address.city)
+// @Success 200 {object} models.Record
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /api-go/records/{UID} [patch]
+func (h *RecordHandler) PatchRecord(c *gin.Context) {
+	if _, ok := requestedFieldsOf[models.Record](c); !ok {
+		return
+	}
+	uid := c.Param("UID")
+	body, err := c.GetRawData()
+	if err != nil {
+		writeRecordError(c, err)
+		return
+	}
+	var patch map[string]any
+	if err := decodeJSONNumbers(body, &patch); err != nil {
+		writeRecordError(c, err)
+		return
+	}
+
+	patched, err := h.records.PatchRecord(uid, func(record *models.Record) error {
+		if err := mergeRecord(record, patch); err != nil {
+			return err
+		}
+		if record.UID != uid {
+			return errUIDMismatch
+		}
+		return binding.Validator.ValidateStruct(record)
+	})
+	if err != nil {
+		writeRecordError(c, err)
+		return
+	}
+	writeRecordJSON[models.Record](c, http.StatusOK, patched, "")
+}
+
+// DeleteRecord removes a record.
+// @Summary Delete record
+// @Description Deletes the record matching the provided UID.
+// @Tags Records
+// @Param UID path string true "Record UID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Router /api-go/records/{UID} [delete]
+func (h *RecordHandler) DeleteRecord(c *gin.Context) {
+	if err := h.records.DeleteRecord(c.Param("UID")); err != nil {
+		writeRecordError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// mergeRecord applies patch to record as a JSON merge patch, so a patched
+// array such as salary replaces the stored one instead of being decoded
+// over its elements.
+func mergeRecord(record *models.Record, patch map[string]any) error {
+	stored, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	var document map[string]any
+	if err := decodeJSONNumbers(stored, &document); err != nil {
+		return err
+	}
+	merged, err := json.Marshal(mergePatch(document, patch))
+	if err != nil {
+		return err
+	}
+	*record = models.Record{}
+	return json.Unmarshal(merged, record)
+}
+
+// mergePatch merges patch into target: objects recursively, any other value
+// by replacement, and null by removing the field.
+func mergePatch(target, patch map[string]any) map[string]any {
+	for key, value := range patch {
+		switch value := value.(type) {
+		case nil:
+			delete(target, key)
+		case map[string]any:
+			nested, _ := target[key].(map[string]any)
+			if nested == nil {
+				nested = map[string]any{}
+			}
+			target[key] = mergePatch(nested, value)
+		default:
+			target[key] = value
+		}
+	}
+	return target
+}
+
+// decodeJSONNumbers decodes data into v keeping numbers as json.Number, so
+// re-encoding them does not lose precision.
+func decodeJSONNumbers(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return &json.SyntaxError{Offset: decoder.InputOffset()}
+	}
+	return nil
+}
+
+// writeRecordError answers a record service, binding or validation error
+// with a problem; bodies that are not record JSON are bad requests.
+func writeRecordError(c *gin.Context, err error) {
+	var validationErrs validator.ValidationErrors
+	switch {
+	case errors.As(err, &validationErrs):
+	case isMalformedJSON(err):
+		err = badRequest("Invalid record body", err)
+	default:
+		err = serviceError(err, "Record operation failed")
+	}
+	abortWithProblem(c, err)
 }
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"craft-fusion/craft-go/recordio"
+	"craft-fusion/craft-go/repository"
+	"craft-fusion/craft-go/services"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportRecords loads records from a CSV or NDJSON request body.
+// @Summary Import records
+// @Description Validates every line of the body against the record model and, only when all lines are valid, writes them in one atomic step. `merge` upserts by UID and keeps other records; `replace` swaps the whole dataset, and is rejected when the body holds no records unless `allowEmpty=true`. CSV input needs a header row using the export column names (any order, any subset); NDJSON holds one record object per line. Records without a UID get one assigned. With `dryRun=true` nothing is written. Invalid input returns a 422 problem whose `errors` name the failing lines, such as `line 12`.
+// @Tags Records
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce json
+// @Param format query string false "Body format; defaults from Content-Type" Enums(csv, ndjson)
+// @Param mode query string false "How to combine with the stored dataset" Enums(merge, replace) default(merge)
+// @Param dryRun query bool false "Validate and report without writing" default(false)
+// @Param allowEmpty query bool false "Let a replace import without records empty the dataset; otherwise it is rejected" default(false)
+// @Success 200 {object} services.ImportResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api-go/records/import [post]
+func (h *RecordHandler) ImportRecords(c *gin.Context) {
+	format := c.Query("format")
+	if format == "" {
+		format = importFormat(c.GetHeader("Content-Type"))
+	}
+	if format != recordio.FormatCSV && format != recordio.FormatNDJSON {
+		abortWithProblem(c, invalidParameter("format"))
+		return
+	}
+	mode := services.ImportMode(c.DefaultQuery("mode", string(services.ImportMerge)))
+	if mode != services.ImportMerge && mode != services.ImportReplace {
+		abortWithProblem(c, invalidParameter("mode"))
+		return
+	}
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dryRun", "false"))
+	if err != nil {
+		abortWithProblem(c, invalidParameter("dryRun"))
+		return
+	}
+	allowEmpty, err := strconv.ParseBool(c.DefaultQuery("allowEmpty", "false"))
+	if err != nil {
+		abortWithProblem(c, invalidParameter("allowEmpty"))
+		return
+	}
+
+	reader, err := recordio.NewReader(c.Request.Body, format)
+	if err != nil {
+		abortWithProblem(c, badRequest(sentence(err.Error()), err))
+		return
+	}
+	result, err := h.records.ImportRecords(reader, services.ImportOptions{Mode: mode, DryRun: dryRun, AllowEmpty: allowEmpty})
+	switch {
+	case errors.Is(err, services.ErrImportRejected):
+		abortWithProblem(c, importRejected(result, err))
+	case errors.Is(err, services.ErrEmptyReplace):
+		abortWithProblem(c, parameterError("allowEmpty", "Replace import has no records; pass allowEmpty=true to empty the dataset"))
+	case err != nil:
+		abortWithProblem(c, asBadRequest(serviceError(err, "Failed to store records")))
+	default:
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// importRejected reports the failing lines of a rejected import as a
+// validation problem, one field error per line.
+func importRejected(result services.ImportResult, err error) error {
+	fields := make([]repository.FieldError, len(result.Errors))
+	for i, lineErr := range result.Errors {
+		fields[i] = repository.FieldError{Field: fmt.Sprintf("line %d", lineErr.Line), Message: lineErr.Error}
+	}
+	detail := fmt.Sprintf("Import rejected: %d of %d lines are invalid", result.ErrorCount, result.Read)
+	return &requestError{problem: problemValidation, detail: detail, fields: fields, err: err}
+}
+
+// importFormat maps a request Content-Type to an import format.
+func importFormat(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv":
+		return recordio.FormatCSV
+	case ndjsonContentType:
+		return recordio.FormatNDJSON
+	}
+	return ""
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// jobHeartbeatInterval is how often an idle event stream sends a comment so
+// proxies and the write deadline do not close it.
+const jobHeartbeatInterval = 15 * time.Second
+
+// StartGenerationJob regenerates the stored dataset in the background.
+// @Summary Start a generation job
+// @Description Starts regenerating the stored dataset like POST /api-go/records/seed, but returns at once. Follow the job with GET /api-go/records/jobs/{id} or its event stream; the dataset is replaced only when the job completes.
+// @Tags Records
+// @Produce json
+// @Param count query int false "Number of records to generate (1-1000000)" default(1000)
+// @Param seed query int false "Generator seed; omit for a random seed reported in the job"
+// @Param profile query string false "Generator profile, see GET /api-go/records/profiles; omit for the server default"
+// @Param locale query string false "Locale of names, addresses, phones and salary currency, such as de-DE; see GET /api-go/records/profiles"
+// @Success 202 {object} services.GenerationJob
+// @Header 202 {string} Location "URL of the job status"
+// @Failure 400 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Header 503 {integer} Retry-After "Seconds to wait before starting another job"
+// @Router /api-go/records/jobs [post]
+func (h *RecordHandler) StartGenerationJob(c *gin.Context) {
+	count, err := strconv.Atoi(c.DefaultQuery("count", "1000"))
+	if err != nil || count <= 0 {
+		abortWithProblem(c, invalidParameter("count"))
+		return
+	}
+	if count > 1000000 {
+		abortWithProblem(c, limitError("count", "Count cannot exceed 1,000,000 records"))
+		return
+	}
+	seed, err := seedQuery(c)
+	if err != nil {
+		abortWithProblem(c, err)
+		return
+	}
+
+	job, err := h.records.StartGenerationJob(count, seed, c.Query("profile"), c.Query("locale"))
+	if err != nil {
+		abortWithProblem(c, asBadRequest(serviceError(err, "Failed to start job")))
+		return
+	}
+	c.Header("Location", "/api-go/records/jobs/"+job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetGenerationJob reports the progress of a generation job.
+// @Summary Get a generation job
+// @Description Returns the status and progress of a generation job. Finished jobs are kept until 100 newer jobs have finished.
+// @Tags Records
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} services.GenerationJob
+// @Failure 404 {object} ErrorResponse
+// @Router /api-go/records/jobs/{id} [get]
+func (h *RecordHandler) GetGenerationJob(c *gin.Context) {
+	job, err := h.records.GenerationJob(c.Param("id"))
+	if err != nil {
+		abortWithProblem(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// CancelGenerationJob stops a running generation job.
+// @Summary Cancel a generation job
+// @Description Stops a running job. The stored dataset is left as it was.
+// @Tags Records
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} services.GenerationJob
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /api-go/records/jobs/{id} [delete]
+func (h *RecordHandler) CancelGenerationJob(c *gin.Context) {
+	job, err := h.records.CancelGenerationJob(c.Param("id"))
+	if err != nil {
+		abortWithProblem(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// StreamGenerationJob streams the progress of a generation job.
+// @Summary Stream generation job progress
+// @Description Server-Sent Events with the job as JSON data: a `progress` event on every change while it runs, then one `completed`, `failed` or `canceled` event before the stream ends. Idle streams receive a comment every 15 seconds.
+// @Tags Records
+// @Produce text/event-stream
+// @Param id path string true "Job ID"
+// @Success 200 {object} services.GenerationJob
+// @Failure 404 {object} ErrorResponse
+// @Router /api-go/records/jobs/{id}/events [get]
+func (h *RecordHandler) StreamGenerationJob(c *gin.Context) {
+	id := c.Param("id")
+	job, changed, err := h.records.WatchGenerationJob(id)
+	if err != nil {
+		abortWithProblem(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	stream := newRecordStream(c, "text/event-stream")
+	heartbeat := time.NewTicker(jobHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		event := "progress"
+		if job.Finished() {
+			event = job.Status
+		}
+		c.SSEvent(event, job)
+		stream.flush()
+		if job.Finished() {
+			return
+		}
+
+		for waiting := true; waiting; {
+			select {
+			case <-changed:
+				waiting = false
+			case <-heartbeat.C:
+				c.Writer.WriteString(": keep-alive\n\n")
+				stream.flush()
+			case <-c.Request.Context().Done():
+				return
+			}
+		}
+		if job, changed, err = h.records.WatchGenerationJob(id); err != nil {
+			return
+		}
+	}
+}
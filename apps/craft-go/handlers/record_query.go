@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"craft-fusion/craft-go/repository"
+	"craft-fusion/craft-go/services"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseRecordQuery reads the paging, sorting and filter parameters shared by
+// the record listing endpoints.
+func parseRecordQuery(c *gin.Context) (services.RecordQuery, error) {
+	query := services.RecordQuery{
+		State:    c.Query("state"),
+		City:     c.Query("city"),
+		LastName: c.Query("lastName"),
+		Zipcode:  c.Query("zipcode"),
+		Q:        c.Query("q"),
+	}
+
+	var err error
+	if query.Page, err = intQuery(c, "page"); err != nil {
+		return query, err
+	}
+	if query.PageSize, err = intQuery(c, "pageSize"); err != nil {
+		return query, err
+	}
+	if query.MinIncome, err = floatQuery(c, "minIncome"); err != nil {
+		return query, err
+	}
+	if query.MaxIncome, err = floatQuery(c, "maxIncome"); err != nil {
+		return query, err
+	}
+	if query.Sort, err = services.ParseSort(c.Query("sort")); err != nil {
+		return query, asBadRequest(err)
+	}
+	return query, nil
+}
+
+// maxLimit is the largest limit the listing endpoints accept.
+const maxLimit = 1000000
+
+// limitQuery reads the limit parameter, or returns fallback when it is
+// absent; 0 means no limit.
+func limitQuery(c *gin.Context, fallback int) (int, error) {
+	raw, ok := c.GetQuery("limit")
+	if !ok {
+		return fallback, nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit <= 0 {
+		return 0, invalidParameter("limit")
+	}
+	if limit > maxLimit {
+		return 0, limitError("limit", "Limit cannot exceed 1,000,000 records")
+	}
+	return limit, nil
+}
+
+func intQuery(c *gin.Context, name string) (int, error) {
+	raw, ok := c.GetQuery(name)
+	if !ok || raw == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, invalidParameter(name)
+	}
+	return value, nil
+}
+
+// seedHeader reports the generator seed so a dataset can be replayed.
+const seedHeader = "X-Record-Seed"
+
+// seedQuery reads the optional seed parameter and resolves it to the non-zero
+// seed that will actually be used for generation.
+func seedQuery(c *gin.Context) (int64, error) {
+	var seed int64
+	if raw := c.Query("seed"); raw != "" {
+		var err error
+		if seed, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			return 0, invalidParameter("seed")
+		}
+	}
+	return repository.ResolveSeed(seed), nil
+}
+
+func floatQuery(c *gin.Context, name string) (*float64, error) {
+	raw, ok := c.GetQuery(name)
+	if !ok || raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, invalidParameter(name)
+	}
+	return &value, nil
+}
+
+// pageLinks builds self/next/prev URLs for a page by rewriting the page
+// parameter of the request URL, keeping every other parameter intact.
+func pageLinks(requestURL *url.URL, page services.RecordPage) PageLinks {
+	link := func(number int) string {
+		values := requestURL.Query()
+		values.Set("page", strconv.Itoa(number))
+		return requestURL.Path + "?" + values.Encode()
+	}
+
+	links := PageLinks{Self: link(page.Page)}
+	if page.PageSize == 0 {
+		return links
+	}
+	if page.Page*page.PageSize < page.Total {
+		links.Next = link(page.Page + 1)
+	}
+	if page.Page > 1 {
+		links.Prev = link(page.Page - 1)
+	}
+	return links
+}
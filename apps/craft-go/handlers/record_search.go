@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"craft-fusion/craft-go/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchRecords serves ranked full-text matches for a query.
+// @Summary Search records
+// @Description Matches every word of `q` against first and last name, email, street, city, state, zipcode and employer name and position. Each word also matches longer words it is a prefix of, so `smi den` finds Smith in Denver. Hits are ranked by field weight and whole-word matches, and carry byte offsets of the matched words for highlighting.
+// @Tags Records
+// @Produce json
+// @Param q query string true "Search words" example(smith denver)
+// @Param limit query int false "Maximum number of hits (1-1000)" default(20)
+// @Param fields query string false "Comma-separated record fields to return, with dots for nested fields; omit for every field" example(UID,firstName,address.city)
+// @Success 200 {object} SearchResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /api-go/records/search [get]
+func (h *RecordHandler) SearchRecords(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 1000 {
+		abortWithProblem(c, invalidParameter("limit"))
+		return
+	}
+	q := c.Query("q")
+	hits, total, err := h.records.SearchRecords(q, limit)
+	if err != nil {
+		abortWithProblem(c, asBadRequest(err))
+		return
+	}
+	writeRecordJSON[models.Record](c, http.StatusOK, SearchResponse{Query: q, Total: total, Hits: hits}, "hits.record")
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"craft-fusion/craft-go/models"
+	"craft-fusion/craft-go/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Record representations selectable with ?version= or the X-Record-Version
+// header. Version 2 is the canonical models.Record; version 1 renders the
+// legacy models.UserRecord shape for older clients.
+const (
+	recordVersionLegacy  = "1"
+	recordVersionCurrent = "2"
+
+	recordVersionHeader = "X-Record-Version"
+)
+
+// recordVersion resolves the record representation requested by the client
+// and echoes it in the response headers.
+func recordVersion(c *gin.Context) (string, error) {
+	version := c.Query("version")
+	if version == "" {
+		version = c.GetHeader(recordVersionHeader)
+	}
+	switch version {
+	case "":
+		version = recordVersionCurrent
+	case recordVersionLegacy, recordVersionCurrent:
+	default:
+		return "", invalidParameter("version")
+	}
+	c.Header(recordVersionHeader, version)
+	return version, nil
+}
+
+// renderRecord writes one record in the requested representation.
+func renderRecord(c *gin.Context, status int, record models.Record, version string) {
+	if version == recordVersionLegacy {
+		writeRecordJSON[models.UserRecord](c, status, models.NewUserRecord(record), "")
+		return
+	}
+	writeRecordJSON[models.Record](c, status, record, "")
+}
+
+// renderRecordArray writes a bare JSON array of records in the requested
+// representation.
+func renderRecordArray(c *gin.Context, records []models.Record, version string) {
+	if version == recordVersionLegacy {
+		writeRecordJSON[models.UserRecord](c, http.StatusOK, models.NewUserRecords(records), "")
+		return
+	}
+	writeRecordJSON[models.Record](c, http.StatusOK, records, "")
+}
+
+// renderRecordsResponse writes a buffered record listing in the requested
+// representation.
+func renderRecordsResponse(c *gin.Context, response RecordsResponse, version string) {
+	if version == recordVersionLegacy {
+		writeRecordJSON[models.UserRecord](c, http.StatusOK, LegacyRecordsResponse{Records: models.NewUserRecords(response.Records), PageInfo: response.PageInfo}, "records")
+		return
+	}
+	writeRecordJSON[models.Record](c, http.StatusOK, response, "records")
+}
+
+// streamRecordsResponse streams the listing query selects in the requested
+// representation; see streamRecords.
+func streamRecordsResponse(c *gin.Context, records *services.RecordService, query services.RecordQuery, mode, version string) {
+	if version == recordVersionLegacy {
+		streamRecords(c, records, query, mode, models.NewUserRecord)
+		return
+	}
+	streamRecords(c, records, query, mode, func(record models.Record) models.Record { return record })
+}
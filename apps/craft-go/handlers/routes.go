@@ -0,0 +1,39 @@
+package handlers
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRecordRoutes mounts the record API under /api-go/records and the
+// Angular compatibility routes under /api/records.
+func RegisterRecordRoutes(router gin.IRouter, records *RecordHandler) {
+	// User Records API
+	router.GET("/api-go/records", records.GetRecords)
+	router.POST("/api-go/records/seed", records.SeedRecords)
+	router.POST("/api-go/records/import", records.ImportRecords)
+	router.POST("/api-go/records/jobs", records.StartGenerationJob)
+	router.GET("/api-go/records/jobs/:id", records.GetGenerationJob)
+	router.GET("/api-go/records/jobs/:id/events", records.StreamGenerationJob)
+	router.DELETE("/api-go/records/jobs/:id", records.CancelGenerationJob)
+	router.GET("/api-go/records/generate", records.GenerateRecords)
+	router.GET("/api-go/records/time", records.GetCreationTime)
+	router.GET("/api-go/records/stats", records.GetGenerationStats)
+	router.GET("/api-go/records/profiles", records.GetProfiles)
+	router.GET("/api-go/records/aggregate", records.AggregateRecords)
+	router.GET("/api-go/records/search", records.SearchRecords)
+	router.GET("/api-go/records/export", records.ExportRecords)
+	router.GET("/api-go/records/total-income/:UID", records.GetTotalIncome)
+	router.GET("/api-go/records/:UID", records.GetRecordByUID)
+	router.POST("/api-go/records", records.CreateRecord)
+	router.PUT("/api-go/records/:UID", records.UpdateRecord)
+	router.PATCH("/api-go/records/:UID", records.PatchRecord)
+	router.DELETE("/api-go/records/:UID", records.DeleteRecord)
+
+	// --- Add these for frontend compatibility ---
+	// If this Go server is ever hit for /api/records/generate, return 501 Not Implemented
+	router.GET("/api/records/generate", NotImplementedHandler)
+	router.GET("/api/records/time", records.GetCreationTime)
+	// Add /api/records and /api/records/:UID for Angular compatibility
+	router.GET("/api/records", records.GetRecords)
+	router.GET("/api/records/total-income/:UID", records.GetTotalIncome)
+	router.GET("/api/records/:UID", records.GetRecordByUID)
+	// -------------------------------------------
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"craft-fusion/craft-go/models"
+	"craft-fusion/craft-go/services"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ndjsonContentType = "application/x-ndjson"
+
+	// streamNDJSON writes one JSON record per line.
+	streamNDJSON = "ndjson"
+	// streamJSON writes the regular RecordsResponse object incrementally.
+	streamJSON = "json"
+
+	// streamFlushInterval is how many records are written between flushes.
+	streamFlushInterval = 1000
+	// streamWriteTimeout bounds each flushed chunk instead of the whole
+	// response, so large listings outlive the server's WriteTimeout while a
+	// stalled client still times out.
+	streamWriteTimeout = 30 * time.Second
+)
+
+// recordStreamMode reports how a record listing should be written: "ndjson"
+// for ?stream=ndjson or an Accept header naming application/x-ndjson, "json"
+// for ?stream=true or ?stream=json, and "" for a buffered response.
+func recordStreamMode(c *gin.Context) string {
+	switch c.Query("stream") {
+	case streamNDJSON:
+		return streamNDJSON
+	case "true", streamJSON:
+		return streamJSON
+	}
+	if strings.Contains(c.GetHeader("Accept"), ndjsonContentType) {
+		return streamNDJSON
+	}
+	return ""
+}
+
+// recordStream writes records to the response in chunks, flushing and
+// extending the write deadline after each one. With gzip enabled, a flush
+// sends whatever the compressor has emitted so far.
+type recordStream struct {
+	c          *gin.Context
+	controller *http.ResponseController
+	encoder    *json.Encoder
+}
+
+func newRecordStream(c *gin.Context, contentType string) *recordStream {
+	stream := &recordStream{
+		c:          c,
+		controller: http.NewResponseController(c.Writer),
+		encoder:    json.NewEncoder(c.Writer),
+	}
+	c.Header("Content-Type", contentType)
+	c.Status(http.StatusOK)
+	stream.flush()
+	return stream
+}
+
+// flush pushes buffered output to the client and renews the write deadline.
+// Deadline errors are ignored: writers that cannot extend it keep the
+// server-wide timeout.
+func (s *recordStream) flush() {
+	_ = s.controller.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	s.c.Writer.Flush()
+}
+
+// write sends p unless the client has gone away.
+func (s *recordStream) write(p []byte) error {
+	if err := s.c.Request.Context().Err(); err != nil {
+		return err
+	}
+	_, err := s.c.Writer.Write(p)
+	return err
+}
+
+// encode writes one record, keeping only the selected fields, followed by a
+// newline.
+func (s *recordStream) encode(record any, fields fieldSelection) error {
+	if fields == nil {
+		if err := s.c.Request.Context().Err(); err != nil {
+			return err
+		}
+		return s.encoder.Encode(record)
+	}
+	projected, err := fields.project(record, "")
+	if err != nil {
+		return err
+	}
+	return s.write(append(projected, '\n'))
+}
+
+// streamRecords writes the page query selects as it is read from the store,
+// in the representation convert produces: one record per line for
+// streamNDJSON, or the RecordsResponse shape c.JSON would produce for
+// streamJSON, with the paging fields after the records. Output is flushed
+// every streamFlushInterval records and stops at the first failed write or
+// once the client goes away. A query error is answered as a problem, since
+// nothing has been written yet.
+func streamRecords[T any](c *gin.Context, records *services.RecordService, query services.RecordQuery, mode string, convert func(models.Record) T) {
+	fields, ok := requestedFieldsOf[T](c)
+	if !ok {
+		return
+	}
+	contentType := ndjsonContentType
+	separator := ""
+	if mode == streamJSON {
+		contentType = "application/json; charset=utf-8"
+		separator = ","
+	}
+
+	var stream *recordStream
+	open := func() error {
+		stream = newRecordStream(c, contentType)
+		if mode == streamJSON {
+			return stream.write([]byte(`{"records":[`))
+		}
+		return nil
+	}
+	written := 0
+	page, err := records.EachRecord(query, func(record models.Record) error {
+		if stream == nil {
+			if err := open(); err != nil {
+				return err
+			}
+		}
+		if written > 0 && separator != "" {
+			if err := stream.write([]byte(separator)); err != nil {
+				return err
+			}
+		}
+		if err := stream.encode(convert(record), fields); err != nil {
+			return err
+		}
+		written++
+		if written%streamFlushInterval == 0 {
+			stream.flush()
+		}
+		return nil
+	})
+	switch {
+	case err != nil && stream == nil:
+		abortWithProblem(c, asBadRequest(err))
+		return
+	case err != nil:
+		return
+	case stream == nil:
+		if open() != nil {
+			return
+		}
+	}
+
+	if mode == streamJSON {
+		// PageInfo marshals as an object; splice its fields after the array.
+		trailer, err := json.Marshal(PageInfo{Total: page.Total, Page: page.Page, PageSize: page.PageSize, Links: pageLinks(c.Request.URL, page)})
+		if err != nil || stream.write([]byte("],")) != nil || stream.write(append(trailer[1:], '\n')) != nil {
+			return
+		}
+	}
+	stream.flush()
+}
@@ -1,10 +1,25 @@
 package handlers
 
-import "craft-fusion/craft-go/models"
+import (
+	"craft-fusion/craft-go/models"
+	"craft-fusion/craft-go/repository"
+)
 
-// ErrorResponse describes a generic API error payload.
+// ErrorResponse describes an RFC 7807 problem, served as
+// application/problem+json.
 type ErrorResponse struct {
-	Error string `json:"error" example:"Invalid limit parameter"`
+	// Type identifies the kind of problem: bad-request, not-found, conflict,
+	// validation, limit-exceeded, internal, not-implemented or unavailable.
+	Type   string `json:"type" example:"https://jeffreysanford.us/problems/validation"`
+	Title  string `json:"title" example:"Validation failed"`
+	Status int    `json:"status" example:"422"`
+	Detail string `json:"detail" example:"Invalid record: firstName is required; salary[0].annualSalary must be gte=0"`
+	// Instance is the request path.
+	Instance string `json:"instance" example:"/api-go/records"`
+	// CorrelationID matches the X-Request-ID response header.
+	CorrelationID string `json:"correlationId" example:"4f9c2a7be0d14c6d8a3e51f0b2c7d9e6"`
+	// Errors lists the invalid fields or parameters, if any.
+	Errors []repository.FieldError `json:"errors,omitempty"`
 }
 
 // HealthResponse describes the API health payload.
@@ -14,10 +29,73 @@ type HealthResponse struct {
 
 // RecordsResponse describes the record list payload.
 type RecordsResponse struct {
+	Records []models.Record `json:"records"`
+	PageInfo
+}
+
+// LegacyRecordsResponse describes the record list payload for version 1 clients.
+type LegacyRecordsResponse struct {
 	Records []models.UserRecord `json:"records"`
+	PageInfo
+}
+
+// PageInfo describes the paging state of a record listing.
+type PageInfo struct {
+	Total    int       `json:"total" example:"1000"`
+	Page     int       `json:"page" example:"1"`
+	PageSize int       `json:"pageSize" example:"25"`
+	Links    PageLinks `json:"links"`
+}
+
+// PageLinks holds navigation URLs for a paged record listing.
+type PageLinks struct {
+	Self string `json:"self" example:"/api-go/records?page=2&pageSize=25"`
+	Next string `json:"next,omitempty" example:"/api-go/records?page=3&pageSize=25"`
+	Prev string `json:"prev,omitempty" example:"/api-go/records?page=1&pageSize=25"`
+}
+
+// SeedResponse describes the result of regenerating the stored dataset.
+type SeedResponse struct {
+	Count int   `json:"count" example:"1000"`
+	Seed  int64 `json:"seed" example:"42"`
 }
 
 // GenerationTimeResponse describes record generation timing in milliseconds.
 type GenerationTimeResponse struct {
 	GenerationTime int64 `json:"generationTime" example:"42"`
 }
+
+// GenerationStatsResponse describes the record generation history.
+type GenerationStatsResponse struct {
+	Runs []models.GenerationRun `json:"runs"`
+}
+
+// SearchResponse describes ranked full-text search results.
+type SearchResponse struct {
+	Query string                 `json:"query" example:"smith denver"`
+	Total int                    `json:"total" example:"12"`
+	Hits  []repository.SearchHit `json:"hits"`
+}
+
+// ProfilesResponse lists the available generator profiles.
+type ProfilesResponse struct {
+	Default  string        `json:"default" example:"full"`
+	Profiles []ProfileInfo `json:"profiles"`
+	Locales  []LocaleInfo  `json:"locales"`
+}
+
+// ProfileInfo describes one generator profile.
+type ProfileInfo struct {
+	Name        string `json:"name" example:"sparse"`
+	Description string `json:"description" example:"Required fields plus a few optional ones, often left empty; zero or one employer."`
+	// Coherent profiles draw city, state, zip and area code together from a
+	// bundled gazetteer.
+	Coherent bool `json:"coherent" example:"true"`
+}
+
+// LocaleInfo describes one generator locale.
+type LocaleInfo struct {
+	Tag      string `json:"tag" example:"de-DE"`
+	Name     string `json:"name" example:"Deutsch (Deutschland)"`
+	Currency string `json:"currency" example:"EUR"`
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"craft-fusion/craft-go/repository"
+	"craft-fusion/craft-go/services"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// errUIDMismatch is returned when a request body names a different UID than the path.
+var errUIDMismatch = &repository.Error{
+	Kind:    repository.KindValidation,
+	Message: "UID in body does not match UID in path",
+	Fields:  []repository.FieldError{{Field: "UID", Message: "must match the UID in the path"}},
+}
+
+func init() {
+	// Report validation failures using the JSON field names clients send.
+	if engine, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		engine.RegisterTagNameFunc(services.JSONFieldName)
+	}
+}
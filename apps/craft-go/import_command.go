@@ -0,0 +1,98 @@
+package main
+
+import (
+	"craft-fusion/craft-go/config"
+	"craft-fusion/craft-go/recordio"
+	"craft-fusion/craft-go/repository"
+	"craft-fusion/craft-go/services"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// runImport implements `craft-go import`, which loads a CSV or NDJSON file
+// into the record store selected by the environment, the same way
+// POST /api-go/records/import does. It returns the process exit code.
+func runImport(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "", "input format, csv or ndjson (default from the file extension)")
+	mode := flags.String("mode", string(services.ImportMerge), "merge to upsert by UID, replace to swap the whole dataset")
+	dryRun := flags.Bool("dry-run", false, "validate and report without writing")
+	allowEmpty := flags.Bool("allow-empty", false, "let a replace import without records empty the dataset")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: craft-go import [-format csv|ndjson] [-mode merge|replace] [-dry-run] [-allow-empty] FILE")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	path := flags.Arg(0)
+	if *format == "" {
+		switch filepath.Ext(path) {
+		case ".csv":
+			*format = recordio.FormatCSV
+		case ".ndjson", ".jsonl":
+			*format = recordio.FormatNDJSON
+		default:
+			fmt.Fprintf(stderr, "%s: cannot infer the format from the extension; pass -format\n", path)
+			return 2
+		}
+	}
+
+	cfg := config.Load()
+	if cfg.RecordStore == config.StoreMemory && !*dryRun {
+		fmt.Fprintln(stderr, "warning: RECORD_STORE=memory keeps imported records only until this command exits; set RECORD_STORE=bolt to persist them")
+	}
+	store, closeStore, err := repository.OpenStore(cfg)
+	if err != nil {
+		// bbolt allows one process at a time, so stop the server first.
+		fmt.Fprintf(stderr, "record store: %s\n", err)
+		return 1
+	}
+	defer closeStore()
+
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer file.Close()
+	reader, err := recordio.NewReader(file, *format)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %s\n", path, err)
+		return 1
+	}
+
+	recordService := services.NewRecordService(store)
+	result, err := recordService.ImportRecords(reader, services.ImportOptions{Mode: services.ImportMode(*mode), DryRun: *dryRun, AllowEmpty: *allowEmpty})
+	for _, lineErr := range result.Errors {
+		fmt.Fprintf(stderr, "%s:%d: %s\n", path, lineErr.Line, lineErr.Error)
+	}
+	if errors.Is(err, services.ErrEmptyReplace) {
+		fmt.Fprintf(stderr, "%s: %s; pass -allow-empty to empty the dataset\n", path, err)
+		return 1
+	}
+	if err != nil && !errors.Is(err, services.ErrImportRejected) {
+		fmt.Fprintf(stderr, "%s: %s\n", path, err)
+		return 1
+	}
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	if encodeErr := encoder.Encode(result); encodeErr != nil {
+		fmt.Fprintf(stderr, "write result: %s\n", encodeErr)
+		return 1
+	}
+	if err != nil {
+		return 1
+	}
+	return 0
+}
@@ -1,17 +1,29 @@
 package main
 
 import (
+	"context"
+	"craft-fusion/craft-go/config"
+	"craft-fusion/craft-go/generator"
+	"craft-fusion/craft-go/graphqlapi"
+	"craft-fusion/craft-go/grpcserver"
 	"craft-fusion/craft-go/handlers"
+	"craft-fusion/craft-go/repository"
+	"craft-fusion/craft-go/services"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-contrib/gzip"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"     // swagger embed files
 	ginSwagger "github.com/swaggo/gin-swagger" // gin-swagger middleware
+	"google.golang.org/grpc"
 
 	docs "craft-fusion/craft-go/docs"
 
@@ -33,6 +45,19 @@ import (
 // @host localhost:4000
 // @BasePath /
 func main() {
+	// Subcommands run instead of the server.
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		os.Exit(runImport(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run serves the HTTP and gRPC APIs until SIGINT, SIGTERM or a server
+// failure, then shuts them down. The record store is closed on every path
+// out once it has been opened.
+func run() (err error) {
 	// Set Gin to release mode if not in development
 	if os.Getenv("GIN_MODE") != "debug" {
 		gin.SetMode(gin.ReleaseMode)
@@ -40,44 +65,72 @@ func main() {
 
 	router := gin.Default()
 
-	// Resolve server port from environment (default 4000)
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "4000"
+	// Resolve server settings from environment (port defaults to 4000)
+	cfg := config.Load()
+	port := cfg.Port
+
+	// Record storage backend (memory or bolt, see config.Load)
+	store, closeStore, err := repository.OpenStore(cfg)
+	if err != nil {
+		return fmt.Errorf("record store: %w", err)
+	}
+	defer func() {
+		if closeErr := closeStore(); closeErr != nil {
+			err = errors.Join(err, fmt.Errorf("close record store: %w", closeErr))
+		}
+	}()
+	log.Printf("Using %s record store with %d records", cfg.RecordStore, store.Count())
+	recordService := services.NewRecordServiceWithStats(store, services.NewGenerationStats(cfg.GenerationHistory))
+	profiles, err := generator.LoadRegistry(cfg.ProfilesDir, cfg.GeneratorProfile)
+	if err != nil {
+		return fmt.Errorf("generator profiles: %w", err)
+	}
+	recordService.SetProfiles(profiles)
+	seed := repository.ResolveSeed(cfg.Seed)
+	if seeded, err := recordService.SeedIfEmpty(cfg.SeedCount, seed); err != nil {
+		return fmt.Errorf("seed records: %w", err)
+	} else if seeded {
+		log.Printf("Seeded record store with %d %s-profile records (seed %d)", cfg.SeedCount, profiles.Default().Name(), seed)
 	}
 
-	// Middleware: Gzip Compression
-	router.Use(gzip.Gzip(gzip.DefaultCompression))
+	// Middleware: Gzip Compression, except for event streams, which proxies
+	// and browsers must see unbuffered, and WebSocket upgrades
+	router.Use(gzip.Gzip(gzip.DefaultCompression,
+		gzip.WithExcludedPathsRegexs([]string{`^/api-go/records/jobs/[^/]+/events$`, `^/api-go/ws/`})))
 
 	// Middleware: CORS
+	allowedOrigins := []string{"http://localhost:4200", "https://jeffreysanford.us", "https://www.jeffreysanford.us", "http://localhost:3000"}
 	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:4200", "https://jeffreysanford.us", "https://www.jeffreysanford.us", "http://localhost:3000"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "X-XSRF-TOKEN"},
+		AllowOrigins:     allowedOrigins,
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "X-XSRF-TOKEN", handlers.CorrelationHeader},
+		ExposeHeaders:    []string{handlers.CorrelationHeader},
 		AllowCredentials: true,
 		MaxAge:           24 * time.Hour,
 	}))
 
+	// Middleware: correlation IDs and RFC 7807 problem responses for errors
+	router.Use(handlers.Problems())
+	router.NoRoute(handlers.RouteNotFound)
+
 	// Health Check
 	router.GET("/api-go/health", handlers.HealthHandler)
 
 	// Add /health endpoint for deployment health checks
 	router.GET("/health", handlers.HealthHandler)
 
-	// User Records API
-	router.GET("/api-go/records", handlers.GetRecordsHandler)
-	router.GET("/api-go/records/generate", handlers.GenerateRecordsHandler)
-	router.GET("/api-go/records/time", handlers.GetCreationTimeHandler)
-	router.GET("/api-go/records/:UID", handlers.GetRecordByUIDHandler)
-
-	// --- Add these for frontend compatibility ---
-	// If this Go server is ever hit for /api/records/generate, return 501 Not Implemented
-	router.GET("/api/records/generate", handlers.NotImplementedHandler)
-	router.GET("/api/records/time", handlers.GetCreationTimeHandler)
-	// Add /api/records and /api/records/:UID for Angular compatibility
-	router.GET("/api/records", handlers.GetRecordsHandler)
-	router.GET("/api/records/:UID", handlers.GetRecordByUIDHandler)
-	// -------------------------------------------
+	// User Records API and Angular compatibility routes
+	handlers.RegisterRecordRoutes(router, handlers.NewRecordHandler(recordService))
+
+	// Live record change feed over WebSocket, for the same origins as CORS
+	handlers.RegisterFeedRoutes(router, handlers.NewRecordFeed(recordService, allowedOrigins))
+
+	// GraphQL for clients that select only the record fields they show
+	graphqlServer, err := graphqlapi.New(recordService, cfg.GraphQLMaxComplexity)
+	if err != nil {
+		return fmt.Errorf("graphql schema: %w", err)
+	}
+	handlers.RegisterGraphQLRoutes(router, handlers.NewRecordGraphQL(graphqlServer))
 
 	// Swagger
 	// Dynamically set the host to the current port to avoid mismatches in dev
@@ -103,7 +156,12 @@ func main() {
 		log.Printf("Endpoint: %s %s", route.Method, fullURL)
 	}
 
-	log.Printf("Starting Go Backend on :%s", port)
+	// gRPC record API on its own port, sharing the record service
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		return fmt.Errorf("grpc listen: %w", err)
+	}
+	grpcServer := grpcserver.New(recordService)
 
 	// Server Configuration
 	srv := &http.Server{
@@ -114,8 +172,55 @@ func main() {
 		IdleTimeout:  30 * time.Second,
 	}
 
-	// Start the Server
-	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("listen: %s\n", err)
+	// Start both servers; the first failure or SIGINT/SIGTERM stops them.
+	serveErrs := make(chan error, 2)
+	go func() {
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			serveErrs <- fmt.Errorf("grpc serve: %w", err)
+		}
+	}()
+	log.Printf("Starting gRPC record API on :%s", cfg.GRPCPort)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErrs <- fmt.Errorf("listen: %w", err)
+		}
+	}()
+	log.Printf("Starting Go Backend on :%s", port)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var serveErr error
+	select {
+	case serveErr = <-serveErrs:
+	case <-ctx.Done():
+		log.Printf("Shutting down")
+	}
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		serveErr = errors.Join(serveErr, fmt.Errorf("http shutdown: %w", err))
+	}
+	stopGRPC(shutdownCtx, grpcServer)
+	return serveErr
+}
+
+// shutdownTimeout bounds how long in-flight requests may take to finish once
+// the servers are asked to stop.
+const shutdownTimeout = 15 * time.Second
+
+// stopGRPC lets in-flight RPCs finish, cancelling them once ctx is done.
+func stopGRPC(ctx context.Context, server *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		server.Stop()
+		<-stopped
 	}
 }
@@ -31,9 +31,11 @@ type Phone struct {
 type Company struct {
 	UID             string  `json:"UID"`
 	EmployeeName    string  `json:"employeeName"`
-	AnnualSalary    float64 `json:"annualSalary"`
-	CompanyName     string  `json:"companyName"`
+	AnnualSalary    float64 `json:"annualSalary" binding:"gte=0"`
+	CompanyName     string  `json:"companyName" binding:"required"`
 	CompanyPosition *string `json:"companyPosition,omitempty"`
+	// Currency is the ISO 4217 code of AnnualSalary; unset means USD.
+	Currency *string `json:"currency,omitempty"`
 }
 
 // Salary represents a salary record
@@ -49,16 +51,43 @@ type Record struct {
 	Name                 string    `json:"name"`
 	Avatar               any       `json:"avatar"`
 	Flicker              any       `json:"flicker"`
-	FirstName            string    `json:"firstName"`
-	LastName             string    `json:"lastName"`
+	FirstName            string    `json:"firstName" binding:"required"`
+	LastName             string    `json:"lastName" binding:"required"`
 	Address              Address   `json:"address"`
 	City                 string    `json:"city"`
 	State                string    `json:"state"`
 	Zip                  string    `json:"zip"`
 	Phone                Phone     `json:"phone"`
-	Salary               []Company `json:"salary"`
-	Email                string    `json:"email"`
+	Salary               []Company `json:"salary" binding:"dive"`
+	Email                string    `json:"email" binding:"omitempty,email"`
 	BirthDate            string    `json:"birthDate"`
-	TotalHouseholdIncome float64   `json:"totalHouseholdIncome"`
+	TotalHouseholdIncome float64   `json:"totalHouseholdIncome" binding:"gte=0"`
 	RegistrationDate     string    `json:"registrationDate"`
 }
+
+// Clone returns a copy of r that shares no slices or pointers with it, so the
+// copy can be changed, or decoded into, without touching r.
+func (r Record) Clone() Record {
+	r.Phone.CountryCode = clonePointer(r.Phone.CountryCode)
+	r.Phone.AreaCode = clonePointer(r.Phone.AreaCode)
+	r.Phone.Extension = clonePointer(r.Phone.Extension)
+	r.Phone.HasExtension = clonePointer(r.Phone.HasExtension)
+	if r.Salary != nil {
+		salary := make([]Company, len(r.Salary))
+		for i, company := range r.Salary {
+			company.CompanyPosition = clonePointer(company.CompanyPosition)
+			company.Currency = clonePointer(company.Currency)
+			salary[i] = company
+		}
+		r.Salary = salary
+	}
+	return r
+}
+
+func clonePointer[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
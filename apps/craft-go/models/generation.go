@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// GenerationRun describes one record generation: how many records were
+// produced, how long it took and which seed reproduces it.
+type GenerationRun struct {
+	Source           string    `json:"source" example:"seed"`
+	Count            int       `json:"count" example:"1000"`
+	DurationMs       float64   `json:"durationMs" example:"12.5"`
+	RecordsPerSecond float64   `json:"recordsPerSecond" example:"80000"`
+	Seed             int64     `json:"seed" example:"42"`
+	Timestamp        time.Time `json:"timestamp"`
+}
@@ -1,6 +1,13 @@
 package models
 
-// UserRecord represents a user record - uses types from the shared craft-library
+import "math"
+
+// DefaultCurrency is the ISO 4217 code assumed for salaries without one.
+const DefaultCurrency = "USD"
+
+// UserRecord is the legacy (version 1) record shape with yearly salaries and
+// an integer household income. Records are stored and generated as Record;
+// UserRecord is only rendered for clients that request version 1.
 type UserRecord struct {
 	UID                  string   `json:"UID"`
 	FirstName            string   `json:"firstName"`
@@ -11,6 +18,39 @@ type UserRecord struct {
 	TotalHouseholdIncome int      `json:"totalHouseholdIncome"`
 }
 
+// NewUserRecord renders a canonical Record in the legacy UserRecord shape.
+// Each employer becomes one Salary entry in the employer's currency, or
+// DefaultCurrency when it has none; Record does not track salary years, so
+// Year is left at 0.
+func NewUserRecord(record Record) UserRecord {
+	salaries := make([]Salary, len(record.Salary))
+	for i, company := range record.Salary {
+		currency := DefaultCurrency
+		if company.Currency != nil {
+			currency = *company.Currency
+		}
+		salaries[i] = Salary{Amount: company.AnnualSalary, Currency: currency}
+	}
+	return UserRecord{
+		UID:                  record.UID,
+		FirstName:            record.FirstName,
+		LastName:             record.LastName,
+		Address:              record.Address,
+		Phone:                record.Phone,
+		Salary:               salaries,
+		TotalHouseholdIncome: int(math.Round(record.TotalHouseholdIncome)),
+	}
+}
+
+// NewUserRecords renders each record in the legacy UserRecord shape.
+func NewUserRecords(records []Record) []UserRecord {
+	legacy := make([]UserRecord, len(records))
+	for i, record := range records {
+		legacy[i] = NewUserRecord(record)
+	}
+	return legacy
+}
+
 // UserEntity represents a user entity.
 type UserEntity struct {
 	ID    int    `json:"id"`
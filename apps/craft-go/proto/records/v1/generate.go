@@ -0,0 +1,5 @@
+// Package recordsv1 is the generated gRPC record API described by
+// records.proto.
+package recordsv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative records/v1/records.proto
@@ -0,0 +1,1047 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: records/v1/records.proto
+
+package recordsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Address struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Street        string                 `protobuf:"bytes,1,opt,name=street,proto3" json:"street,omitempty"`
+	City          string                 `protobuf:"bytes,2,opt,name=city,proto3" json:"city,omitempty"`
+	State         string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	Zipcode       string                 `protobuf:"bytes,4,opt,name=zipcode,proto3" json:"zipcode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_records_v1_records_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_records_v1_records_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_records_v1_records_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Address) GetStreet() string {
+	if x != nil {
+		return x.Street
+	}
+	return ""
+}
+
+func (x *Address) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Address) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Address) GetZipcode() string {
+	if x != nil {
+		return x.Zipcode
+	}
+	return ""
+}
+
+type Phone struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uid           string                 `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	Number        string                 `protobuf:"bytes,2,opt,name=number,proto3" json:"number,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	CountryCode   *string                `protobuf:"bytes,4,opt,name=country_code,json=countryCode,proto3,oneof" json:"country_code,omitempty"`
+	AreaCode      *string                `protobuf:"bytes,5,opt,name=area_code,json=areaCode,proto3,oneof" json:"area_code,omitempty"`
+	Extension     *string                `protobuf:"bytes,6,opt,name=extension,proto3,oneof" json:"extension,omitempty"`
+	HasExtension  *bool                  `protobuf:"varint,7,opt,name=has_extension,json=hasExtension,proto3,oneof" json:"has_extension,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Phone) Reset() {
+	*x = Phone{}
+	mi := &file_records_v1_records_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Phone) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Phone) ProtoMessage() {}
+
+func (x *Phone) ProtoReflect() protoreflect.Message {
+	mi := &file_records_v1_records_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Phone.ProtoReflect.Descriptor instead.
+func (*Phone) Descriptor() ([]byte, []int) {
+	return file_records_v1_records_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Phone) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *Phone) GetNumber() string {
+	if x != nil {
+		return x.Number
+	}
+	return ""
+}
+
+func (x *Phone) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Phone) GetCountryCode() string {
+	if x != nil && x.CountryCode != nil {
+		return *x.CountryCode
+	}
+	return ""
+}
+
+func (x *Phone) GetAreaCode() string {
+	if x != nil && x.AreaCode != nil {
+		return *x.AreaCode
+	}
+	return ""
+}
+
+func (x *Phone) GetExtension() string {
+	if x != nil && x.Extension != nil {
+		return *x.Extension
+	}
+	return ""
+}
+
+func (x *Phone) GetHasExtension() bool {
+	if x != nil && x.HasExtension != nil {
+		return *x.HasExtension
+	}
+	return false
+}
+
+type Company struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Uid             string                 `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	EmployeeName    string                 `protobuf:"bytes,2,opt,name=employee_name,json=employeeName,proto3" json:"employee_name,omitempty"`
+	AnnualSalary    float64                `protobuf:"fixed64,3,opt,name=annual_salary,json=annualSalary,proto3" json:"annual_salary,omitempty"`
+	CompanyName     string                 `protobuf:"bytes,4,opt,name=company_name,json=companyName,proto3" json:"company_name,omitempty"`
+	CompanyPosition *string                `protobuf:"bytes,5,opt,name=company_position,json=companyPosition,proto3,oneof" json:"company_position,omitempty"`
+	// ISO 4217 code of annual_salary; unset means USD.
+	Currency      *string `protobuf:"bytes,6,opt,name=currency,proto3,oneof" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Company) Reset() {
+	*x = Company{}
+	mi := &file_records_v1_records_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Company) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Company) ProtoMessage() {}
+
+func (x *Company) ProtoReflect() protoreflect.Message {
+	mi := &file_records_v1_records_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Company.ProtoReflect.Descriptor instead.
+func (*Company) Descriptor() ([]byte, []int) {
+	return file_records_v1_records_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Company) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *Company) GetEmployeeName() string {
+	if x != nil {
+		return x.EmployeeName
+	}
+	return ""
+}
+
+func (x *Company) GetAnnualSalary() float64 {
+	if x != nil {
+		return x.AnnualSalary
+	}
+	return 0
+}
+
+func (x *Company) GetCompanyName() string {
+	if x != nil {
+		return x.CompanyName
+	}
+	return ""
+}
+
+func (x *Company) GetCompanyPosition() string {
+	if x != nil && x.CompanyPosition != nil {
+		return *x.CompanyPosition
+	}
+	return ""
+}
+
+func (x *Company) GetCurrency() string {
+	if x != nil && x.Currency != nil {
+		return *x.Currency
+	}
+	return ""
+}
+
+// Record mirrors the JSON record, without the unused avatar and flicker
+// fields.
+type Record struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Uid                  string                 `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	Name                 string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	FirstName            string                 `protobuf:"bytes,3,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName             string                 `protobuf:"bytes,4,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Address              *Address               `protobuf:"bytes,5,opt,name=address,proto3" json:"address,omitempty"`
+	City                 string                 `protobuf:"bytes,6,opt,name=city,proto3" json:"city,omitempty"`
+	State                string                 `protobuf:"bytes,7,opt,name=state,proto3" json:"state,omitempty"`
+	Zip                  string                 `protobuf:"bytes,8,opt,name=zip,proto3" json:"zip,omitempty"`
+	Phone                *Phone                 `protobuf:"bytes,9,opt,name=phone,proto3" json:"phone,omitempty"`
+	Salary               []*Company             `protobuf:"bytes,10,rep,name=salary,proto3" json:"salary,omitempty"`
+	Email                string                 `protobuf:"bytes,11,opt,name=email,proto3" json:"email,omitempty"`
+	BirthDate            string                 `protobuf:"bytes,12,opt,name=birth_date,json=birthDate,proto3" json:"birth_date,omitempty"`
+	TotalHouseholdIncome float64                `protobuf:"fixed64,13,opt,name=total_household_income,json=totalHouseholdIncome,proto3" json:"total_household_income,omitempty"`
+	RegistrationDate     string                 `protobuf:"bytes,14,opt,name=registration_date,json=registrationDate,proto3" json:"registration_date,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Record) Reset() {
+	*x = Record{}
+	mi := &file_records_v1_records_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Record) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Record) ProtoMessage() {}
+
+func (x *Record) ProtoReflect() protoreflect.Message {
+	mi := &file_records_v1_records_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Record.ProtoReflect.Descriptor instead.
+func (*Record) Descriptor() ([]byte, []int) {
+	return file_records_v1_records_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Record) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *Record) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Record) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *Record) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *Record) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *Record) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Record) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Record) GetZip() string {
+	if x != nil {
+		return x.Zip
+	}
+	return ""
+}
+
+func (x *Record) GetPhone() *Phone {
+	if x != nil {
+		return x.Phone
+	}
+	return nil
+}
+
+func (x *Record) GetSalary() []*Company {
+	if x != nil {
+		return x.Salary
+	}
+	return nil
+}
+
+func (x *Record) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Record) GetBirthDate() string {
+	if x != nil {
+		return x.BirthDate
+	}
+	return ""
+}
+
+func (x *Record) GetTotalHouseholdIncome() float64 {
+	if x != nil {
+		return x.TotalHouseholdIncome
+	}
+	return 0
+}
+
+func (x *Record) GetRegistrationDate() string {
+	if x != nil {
+		return x.RegistrationDate
+	}
+	return ""
+}
+
+// ListRecordsRequest takes the filters, sorting and paging of
+// GET /api-go/records. Zero values mean no constraint.
+type ListRecordsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Page  int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	// page_size 0 returns every match, up to limit.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// limit caps an unpaged listing: 1000 by default for ListRecords and
+	// unlimited for StreamRecords, at most 1,000,000.
+	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// sort is a comma-separated field list such as "lastName,-totalHouseholdIncome".
+	Sort      string   `protobuf:"bytes,4,opt,name=sort,proto3" json:"sort,omitempty"`
+	State     string   `protobuf:"bytes,5,opt,name=state,proto3" json:"state,omitempty"`
+	City      string   `protobuf:"bytes,6,opt,name=city,proto3" json:"city,omitempty"`
+	LastName  string   `protobuf:"bytes,7,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Zipcode   string   `protobuf:"bytes,8,opt,name=zipcode,proto3" json:"zipcode,omitempty"`
+	MinIncome *float64 `protobuf:"fixed64,9,opt,name=min_income,json=minIncome,proto3,oneof" json:"min_income,omitempty"`
+	MaxIncome *float64 `protobuf:"fixed64,10,opt,name=max_income,json=maxIncome,proto3,oneof" json:"max_income,omitempty"`
+	// q matches case-insensitive substrings of names, address and email.
+	Q             string `protobuf:"bytes,11,opt,name=q,proto3" json:"q,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRecordsRequest) Reset() {
+	*x = ListRecordsRequest{}
+	mi := &file_records_v1_records_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRecordsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRecordsRequest) ProtoMessage() {}
+
+func (x *ListRecordsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_records_v1_records_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRecordsRequest.ProtoReflect.Descriptor instead.
+func (*ListRecordsRequest) Descriptor() ([]byte, []int) {
+	return file_records_v1_records_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListRecordsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListRecordsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListRecordsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListRecordsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListRecordsRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ListRecordsRequest) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *ListRecordsRequest) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *ListRecordsRequest) GetZipcode() string {
+	if x != nil {
+		return x.Zipcode
+	}
+	return ""
+}
+
+func (x *ListRecordsRequest) GetMinIncome() float64 {
+	if x != nil && x.MinIncome != nil {
+		return *x.MinIncome
+	}
+	return 0
+}
+
+func (x *ListRecordsRequest) GetMaxIncome() float64 {
+	if x != nil && x.MaxIncome != nil {
+		return *x.MaxIncome
+	}
+	return 0
+}
+
+func (x *ListRecordsRequest) GetQ() string {
+	if x != nil {
+		return x.Q
+	}
+	return ""
+}
+
+type ListRecordsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*Record              `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRecordsResponse) Reset() {
+	*x = ListRecordsResponse{}
+	mi := &file_records_v1_records_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRecordsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRecordsResponse) ProtoMessage() {}
+
+func (x *ListRecordsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_records_v1_records_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRecordsResponse.ProtoReflect.Descriptor instead.
+func (*ListRecordsResponse) Descriptor() ([]byte, []int) {
+	return file_records_v1_records_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListRecordsResponse) GetRecords() []*Record {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *ListRecordsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListRecordsResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListRecordsResponse) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+type GetRecordRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uid           string                 `protobuf:"bytes,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecordRequest) Reset() {
+	*x = GetRecordRequest{}
+	mi := &file_records_v1_records_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecordRequest) ProtoMessage() {}
+
+func (x *GetRecordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_records_v1_records_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecordRequest.ProtoReflect.Descriptor instead.
+func (*GetRecordRequest) Descriptor() ([]byte, []int) {
+	return file_records_v1_records_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetRecordRequest) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+type CreateRecordRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Record        *Record                `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRecordRequest) Reset() {
+	*x = CreateRecordRequest{}
+	mi := &file_records_v1_records_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRecordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRecordRequest) ProtoMessage() {}
+
+func (x *CreateRecordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_records_v1_records_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRecordRequest.ProtoReflect.Descriptor instead.
+func (*CreateRecordRequest) Descriptor() ([]byte, []int) {
+	return file_records_v1_records_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CreateRecordRequest) GetRecord() *Record {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+type GetGenerationStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGenerationStatsRequest) Reset() {
+	*x = GetGenerationStatsRequest{}
+	mi := &file_records_v1_records_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGenerationStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGenerationStatsRequest) ProtoMessage() {}
+
+func (x *GetGenerationStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_records_v1_records_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGenerationStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetGenerationStatsRequest) Descriptor() ([]byte, []int) {
+	return file_records_v1_records_proto_rawDescGZIP(), []int{8}
+}
+
+// GenerationRun describes one record generation.
+type GenerationRun struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// source is seed, generate or job.
+	Source           string  `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Count            int32   `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	DurationMs       float64 `protobuf:"fixed64,3,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	RecordsPerSecond float64 `protobuf:"fixed64,4,opt,name=records_per_second,json=recordsPerSecond,proto3" json:"records_per_second,omitempty"`
+	// seed reproduces the generated records.
+	Seed          int64                  `protobuf:"varint,5,opt,name=seed,proto3" json:"seed,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerationRun) Reset() {
+	*x = GenerationRun{}
+	mi := &file_records_v1_records_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerationRun) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerationRun) ProtoMessage() {}
+
+func (x *GenerationRun) ProtoReflect() protoreflect.Message {
+	mi := &file_records_v1_records_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerationRun.ProtoReflect.Descriptor instead.
+func (*GenerationRun) Descriptor() ([]byte, []int) {
+	return file_records_v1_records_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GenerationRun) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *GenerationRun) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *GenerationRun) GetDurationMs() float64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *GenerationRun) GetRecordsPerSecond() float64 {
+	if x != nil {
+		return x.RecordsPerSecond
+	}
+	return 0
+}
+
+func (x *GenerationRun) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *GenerationRun) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type GetGenerationStatsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// runs are ordered oldest first.
+	Runs          []*GenerationRun `protobuf:"bytes,1,rep,name=runs,proto3" json:"runs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGenerationStatsResponse) Reset() {
+	*x = GetGenerationStatsResponse{}
+	mi := &file_records_v1_records_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGenerationStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGenerationStatsResponse) ProtoMessage() {}
+
+func (x *GetGenerationStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_records_v1_records_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGenerationStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetGenerationStatsResponse) Descriptor() ([]byte, []int) {
+	return file_records_v1_records_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetGenerationStatsResponse) GetRuns() []*GenerationRun {
+	if x != nil {
+		return x.Runs
+	}
+	return nil
+}
+
+var File_records_v1_records_proto protoreflect.FileDescriptor
+
+const file_records_v1_records_proto_rawDesc = "" +
+	"\n" +
+	"\x18records/v1/records.proto\x12\x12craftgo.records.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"e\n" +
+	"\aAddress\x12\x16\n" +
+	"\x06street\x18\x01 \x01(\tR\x06street\x12\x12\n" +
+	"\x04city\x18\x02 \x01(\tR\x04city\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\x12\x18\n" +
+	"\azipcode\x18\x04 \x01(\tR\azipcode\"\x9b\x02\n" +
+	"\x05Phone\x12\x10\n" +
+	"\x03uid\x18\x01 \x01(\tR\x03uid\x12\x16\n" +
+	"\x06number\x18\x02 \x01(\tR\x06number\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12&\n" +
+	"\fcountry_code\x18\x04 \x01(\tH\x00R\vcountryCode\x88\x01\x01\x12 \n" +
+	"\tarea_code\x18\x05 \x01(\tH\x01R\bareaCode\x88\x01\x01\x12!\n" +
+	"\textension\x18\x06 \x01(\tH\x02R\textension\x88\x01\x01\x12(\n" +
+	"\rhas_extension\x18\a \x01(\bH\x03R\fhasExtension\x88\x01\x01B\x0f\n" +
+	"\r_country_codeB\f\n" +
+	"\n" +
+	"_area_codeB\f\n" +
+	"\n" +
+	"_extensionB\x10\n" +
+	"\x0e_has_extension\"\xfb\x01\n" +
+	"\aCompany\x12\x10\n" +
+	"\x03uid\x18\x01 \x01(\tR\x03uid\x12#\n" +
+	"\remployee_name\x18\x02 \x01(\tR\femployeeName\x12#\n" +
+	"\rannual_salary\x18\x03 \x01(\x01R\fannualSalary\x12!\n" +
+	"\fcompany_name\x18\x04 \x01(\tR\vcompanyName\x12.\n" +
+	"\x10company_position\x18\x05 \x01(\tH\x00R\x0fcompanyPosition\x88\x01\x01\x12\x1f\n" +
+	"\bcurrency\x18\x06 \x01(\tH\x01R\bcurrency\x88\x01\x01B\x13\n" +
+	"\x11_company_positionB\v\n" +
+	"\t_currency\"\xdb\x03\n" +
+	"\x06Record\x12\x10\n" +
+	"\x03uid\x18\x01 \x01(\tR\x03uid\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"first_name\x18\x03 \x01(\tR\tfirstName\x12\x1b\n" +
+	"\tlast_name\x18\x04 \x01(\tR\blastName\x125\n" +
+	"\aaddress\x18\x05 \x01(\v2\x1b.craftgo.records.v1.AddressR\aaddress\x12\x12\n" +
+	"\x04city\x18\x06 \x01(\tR\x04city\x12\x14\n" +
+	"\x05state\x18\a \x01(\tR\x05state\x12\x10\n" +
+	"\x03zip\x18\b \x01(\tR\x03zip\x12/\n" +
+	"\x05phone\x18\t \x01(\v2\x19.craftgo.records.v1.PhoneR\x05phone\x123\n" +
+	"\x06salary\x18\n" +
+	" \x03(\v2\x1b.craftgo.records.v1.CompanyR\x06salary\x12\x14\n" +
+	"\x05email\x18\v \x01(\tR\x05email\x12\x1d\n" +
+	"\n" +
+	"birth_date\x18\f \x01(\tR\tbirthDate\x124\n" +
+	"\x16total_household_income\x18\r \x01(\x01R\x14totalHouseholdIncome\x12+\n" +
+	"\x11registration_date\x18\x0e \x01(\tR\x10registrationDate\"\xc4\x02\n" +
+	"\x12ListRecordsRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x12\n" +
+	"\x04sort\x18\x04 \x01(\tR\x04sort\x12\x14\n" +
+	"\x05state\x18\x05 \x01(\tR\x05state\x12\x12\n" +
+	"\x04city\x18\x06 \x01(\tR\x04city\x12\x1b\n" +
+	"\tlast_name\x18\a \x01(\tR\blastName\x12\x18\n" +
+	"\azipcode\x18\b \x01(\tR\azipcode\x12\"\n" +
+	"\n" +
+	"min_income\x18\t \x01(\x01H\x00R\tminIncome\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"max_income\x18\n" +
+	" \x01(\x01H\x01R\tmaxIncome\x88\x01\x01\x12\f\n" +
+	"\x01q\x18\v \x01(\tR\x01qB\r\n" +
+	"\v_min_incomeB\r\n" +
+	"\v_max_income\"\x92\x01\n" +
+	"\x13ListRecordsResponse\x124\n" +
+	"\arecords\x18\x01 \x03(\v2\x1a.craftgo.records.v1.RecordR\arecords\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\"$\n" +
+	"\x10GetRecordRequest\x12\x10\n" +
+	"\x03uid\x18\x01 \x01(\tR\x03uid\"I\n" +
+	"\x13CreateRecordRequest\x122\n" +
+	"\x06record\x18\x01 \x01(\v2\x1a.craftgo.records.v1.RecordR\x06record\"\x1b\n" +
+	"\x19GetGenerationStatsRequest\"\xda\x01\n" +
+	"\rGenerationRun\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x12\x1f\n" +
+	"\vduration_ms\x18\x03 \x01(\x01R\n" +
+	"durationMs\x12,\n" +
+	"\x12records_per_second\x18\x04 \x01(\x01R\x10recordsPerSecond\x12\x12\n" +
+	"\x04seed\x18\x05 \x01(\x03R\x04seed\x128\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"S\n" +
+	"\x1aGetGenerationStatsResponse\x125\n" +
+	"\x04runs\x18\x01 \x03(\v2!.craftgo.records.v1.GenerationRunR\x04runs2\xdf\x03\n" +
+	"\rRecordService\x12^\n" +
+	"\vListRecords\x12&.craftgo.records.v1.ListRecordsRequest\x1a'.craftgo.records.v1.ListRecordsResponse\x12U\n" +
+	"\rStreamRecords\x12&.craftgo.records.v1.ListRecordsRequest\x1a\x1a.craftgo.records.v1.Record0\x01\x12M\n" +
+	"\tGetRecord\x12$.craftgo.records.v1.GetRecordRequest\x1a\x1a.craftgo.records.v1.Record\x12S\n" +
+	"\fCreateRecord\x12'.craftgo.records.v1.CreateRecordRequest\x1a\x1a.craftgo.records.v1.Record\x12s\n" +
+	"\x12GetGenerationStats\x12-.craftgo.records.v1.GetGenerationStatsRequest\x1a..craftgo.records.v1.GetGenerationStatsResponseB2Z0craft-fusion/craft-go/proto/records/v1;recordsv1b\x06proto3"
+
+var (
+	file_records_v1_records_proto_rawDescOnce sync.Once
+	file_records_v1_records_proto_rawDescData []byte
+)
+
+func file_records_v1_records_proto_rawDescGZIP() []byte {
+	file_records_v1_records_proto_rawDescOnce.Do(func() {
+		file_records_v1_records_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_records_v1_records_proto_rawDesc), len(file_records_v1_records_proto_rawDesc)))
+	})
+	return file_records_v1_records_proto_rawDescData
+}
+
+var file_records_v1_records_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_records_v1_records_proto_goTypes = []any{
+	(*Address)(nil),                    // 0: craftgo.records.v1.Address
+	(*Phone)(nil),                      // 1: craftgo.records.v1.Phone
+	(*Company)(nil),                    // 2: craftgo.records.v1.Company
+	(*Record)(nil),                     // 3: craftgo.records.v1.Record
+	(*ListRecordsRequest)(nil),         // 4: craftgo.records.v1.ListRecordsRequest
+	(*ListRecordsResponse)(nil),        // 5: craftgo.records.v1.ListRecordsResponse
+	(*GetRecordRequest)(nil),           // 6: craftgo.records.v1.GetRecordRequest
+	(*CreateRecordRequest)(nil),        // 7: craftgo.records.v1.CreateRecordRequest
+	(*GetGenerationStatsRequest)(nil),  // 8: craftgo.records.v1.GetGenerationStatsRequest
+	(*GenerationRun)(nil),              // 9: craftgo.records.v1.GenerationRun
+	(*GetGenerationStatsResponse)(nil), // 10: craftgo.records.v1.GetGenerationStatsResponse
+	(*timestamppb.Timestamp)(nil),      // 11: google.protobuf.Timestamp
+}
+var file_records_v1_records_proto_depIdxs = []int32{
+	0,  // 0: craftgo.records.v1.Record.address:type_name -> craftgo.records.v1.Address
+	1,  // 1: craftgo.records.v1.Record.phone:type_name -> craftgo.records.v1.Phone
+	2,  // 2: craftgo.records.v1.Record.salary:type_name -> craftgo.records.v1.Company
+	3,  // 3: craftgo.records.v1.ListRecordsResponse.records:type_name -> craftgo.records.v1.Record
+	3,  // 4: craftgo.records.v1.CreateRecordRequest.record:type_name -> craftgo.records.v1.Record
+	11, // 5: craftgo.records.v1.GenerationRun.timestamp:type_name -> google.protobuf.Timestamp
+	9,  // 6: craftgo.records.v1.GetGenerationStatsResponse.runs:type_name -> craftgo.records.v1.GenerationRun
+	4,  // 7: craftgo.records.v1.RecordService.ListRecords:input_type -> craftgo.records.v1.ListRecordsRequest
+	4,  // 8: craftgo.records.v1.RecordService.StreamRecords:input_type -> craftgo.records.v1.ListRecordsRequest
+	6,  // 9: craftgo.records.v1.RecordService.GetRecord:input_type -> craftgo.records.v1.GetRecordRequest
+	7,  // 10: craftgo.records.v1.RecordService.CreateRecord:input_type -> craftgo.records.v1.CreateRecordRequest
+	8,  // 11: craftgo.records.v1.RecordService.GetGenerationStats:input_type -> craftgo.records.v1.GetGenerationStatsRequest
+	5,  // 12: craftgo.records.v1.RecordService.ListRecords:output_type -> craftgo.records.v1.ListRecordsResponse
+	3,  // 13: craftgo.records.v1.RecordService.StreamRecords:output_type -> craftgo.records.v1.Record
+	3,  // 14: craftgo.records.v1.RecordService.GetRecord:output_type -> craftgo.records.v1.Record
+	3,  // 15: craftgo.records.v1.RecordService.CreateRecord:output_type -> craftgo.records.v1.Record
+	10, // 16: craftgo.records.v1.RecordService.GetGenerationStats:output_type -> craftgo.records.v1.GetGenerationStatsResponse
+	12, // [12:17] is the sub-list for method output_type
+	7,  // [7:12] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_records_v1_records_proto_init() }
+func file_records_v1_records_proto_init() {
+	if File_records_v1_records_proto != nil {
+		return
+	}
+	file_records_v1_records_proto_msgTypes[1].OneofWrappers = []any{}
+	file_records_v1_records_proto_msgTypes[2].OneofWrappers = []any{}
+	file_records_v1_records_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_records_v1_records_proto_rawDesc), len(file_records_v1_records_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_records_v1_records_proto_goTypes,
+		DependencyIndexes: file_records_v1_records_proto_depIdxs,
+		MessageInfos:      file_records_v1_records_proto_msgTypes,
+	}.Build()
+	File_records_v1_records_proto = out.File
+	file_records_v1_records_proto_goTypes = nil
+	file_records_v1_records_proto_depIdxs = nil
+}
@@ -0,0 +1,218 @@
+// Package recordio converts records to and from flat tabular files.
+//
+// Records are flattened into one row per record with these columns, in order:
+//
+//	UID, name, firstName, lastName, email, birthDate, registrationDate,
+//	totalHouseholdIncome,
+//	address.street, address.city, address.state, address.zipcode,
+//	city, state, zip,
+//	phone.UID, phone.number, phone.type, phone.countryCode, phone.areaCode,
+//	phone.extension, phone.hasExtension,
+//
+// followed by six columns for every company slot N, counting from 0:
+//
+//	salary[N].UID, salary[N].employeeName, salary[N].companyName,
+//	salary[N].companyPosition, salary[N].annualSalary, salary[N].currency
+//
+// A file has as many company slots as the record with the most companies;
+// records with fewer leave the extra slots empty. Optional fields that are
+// unset are written as empty cells. The free-form avatar and flicker fields
+// are not exported.
+package recordio
+
+import (
+	"craft-fusion/craft-go/models"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// column maps one flat column to a record field.
+type column struct {
+	name string
+	// numeric columns are written as numbers by formats that distinguish them.
+	numeric bool
+	get     func(record *models.Record) string
+	set     func(record *models.Record, value string) error
+}
+
+func textColumn[T any](name string, owner func(*models.Record) *T, ref func(*T) *string) column {
+	return column{
+		name: name,
+		get:  func(r *models.Record) string { return *ref(owner(r)) },
+		set:  func(r *models.Record, value string) error { *ref(owner(r)) = value; return nil },
+	}
+}
+
+func optionalColumn[T any](name string, owner func(*models.Record) *T, ref func(*T) **string) column {
+	return column{
+		name: name,
+		get:  func(r *models.Record) string { return stringValue(*ref(owner(r))) },
+		set: func(r *models.Record, value string) error {
+			if value != "" {
+				*ref(owner(r)) = &value
+			}
+			return nil
+		},
+	}
+}
+
+func numberColumn[T any](name string, owner func(*models.Record) *T, ref func(*T) *float64) column {
+	return column{
+		name:    name,
+		numeric: true,
+		get:     func(r *models.Record) string { return formatFloat(*ref(owner(r))) },
+		set: func(r *models.Record, value string) error {
+			if value == "" {
+				return nil
+			}
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("%s must be a number", name)
+			}
+			*ref(owner(r)) = number
+			return nil
+		},
+	}
+}
+
+func recordOf(r *models.Record) *models.Record   { return r }
+func addressOf(r *models.Record) *models.Address { return &r.Address }
+func phoneOf(r *models.Record) *models.Phone     { return &r.Phone }
+
+var recordColumns = []column{
+	textColumn("UID", recordOf, func(r *models.Record) *string { return &r.UID }),
+	textColumn("name", recordOf, func(r *models.Record) *string { return &r.Name }),
+	textColumn("firstName", recordOf, func(r *models.Record) *string { return &r.FirstName }),
+	textColumn("lastName", recordOf, func(r *models.Record) *string { return &r.LastName }),
+	textColumn("email", recordOf, func(r *models.Record) *string { return &r.Email }),
+	textColumn("birthDate", recordOf, func(r *models.Record) *string { return &r.BirthDate }),
+	textColumn("registrationDate", recordOf, func(r *models.Record) *string { return &r.RegistrationDate }),
+	numberColumn("totalHouseholdIncome", recordOf, func(r *models.Record) *float64 { return &r.TotalHouseholdIncome }),
+	textColumn("address.street", addressOf, func(a *models.Address) *string { return &a.Street }),
+	textColumn("address.city", addressOf, func(a *models.Address) *string { return &a.City }),
+	textColumn("address.state", addressOf, func(a *models.Address) *string { return &a.State }),
+	textColumn("address.zipcode", addressOf, func(a *models.Address) *string { return &a.Zipcode }),
+	textColumn("city", recordOf, func(r *models.Record) *string { return &r.City }),
+	textColumn("state", recordOf, func(r *models.Record) *string { return &r.State }),
+	textColumn("zip", recordOf, func(r *models.Record) *string { return &r.Zip }),
+	textColumn("phone.UID", phoneOf, func(p *models.Phone) *string { return &p.UID }),
+	textColumn("phone.number", phoneOf, func(p *models.Phone) *string { return &p.Number }),
+	textColumn("phone.type", phoneOf, func(p *models.Phone) *string { return &p.Type }),
+	optionalColumn("phone.countryCode", phoneOf, func(p *models.Phone) **string { return &p.CountryCode }),
+	optionalColumn("phone.areaCode", phoneOf, func(p *models.Phone) **string { return &p.AreaCode }),
+	optionalColumn("phone.extension", phoneOf, func(p *models.Phone) **string { return &p.Extension }),
+	{
+		name: "phone.hasExtension",
+		get: func(r *models.Record) string {
+			if r.Phone.HasExtension == nil {
+				return ""
+			}
+			return strconv.FormatBool(*r.Phone.HasExtension)
+		},
+		set: func(r *models.Record, value string) error {
+			if value == "" {
+				return nil
+			}
+			hasExtension, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("phone.hasExtension must be true or false")
+			}
+			r.Phone.HasExtension = &hasExtension
+			return nil
+		},
+	},
+}
+
+// companyColumns returns the columns of company slot n. Reading a slot the
+// record does not have yields empty cells; writing to one grows Salary.
+func companyColumns(n int) []column {
+	company := func(r *models.Record) *models.Company {
+		if n >= len(r.Salary) {
+			r.Salary = append(r.Salary, make([]models.Company, n+1-len(r.Salary))...)
+		}
+		return &r.Salary[n]
+	}
+	slot := func(c column) column {
+		get := c.get
+		c.get = func(r *models.Record) string {
+			if n >= len(r.Salary) {
+				return ""
+			}
+			return get(r)
+		}
+		return c
+	}
+	prefix := fmt.Sprintf("salary[%d].", n)
+	return []column{
+		slot(textColumn(prefix+"UID", company, func(c *models.Company) *string { return &c.UID })),
+		slot(textColumn(prefix+"employeeName", company, func(c *models.Company) *string { return &c.EmployeeName })),
+		slot(textColumn(prefix+"companyName", company, func(c *models.Company) *string { return &c.CompanyName })),
+		slot(optionalColumn(prefix+"companyPosition", company, func(c *models.Company) **string { return &c.CompanyPosition })),
+		slot(numberColumn(prefix+"annualSalary", company, func(c *models.Company) *float64 { return &c.AnnualSalary })),
+		slot(optionalColumn(prefix+"currency", company, func(c *models.Company) **string { return &c.Currency })),
+	}
+}
+
+// ErrLayoutTooNarrow is returned when writing a record with more companies
+// than the file's layout has slots for, rather than dropping the extra ones.
+var ErrLayoutTooNarrow = errors.New("record has more companies than the layout holds")
+
+// Layout is the column set of one flat file.
+type Layout struct {
+	columns      []column
+	companySlots int
+}
+
+// NewLayout returns the columns needed to hold records with up to
+// companySlots companies each.
+func NewLayout(companySlots int) Layout {
+	columns := append([]column(nil), recordColumns...)
+	for n := 0; n < companySlots; n++ {
+		columns = append(columns, companyColumns(n)...)
+	}
+	return Layout{columns: columns, companySlots: companySlots}
+}
+
+// LayoutFor returns the layout fitting every given record.
+func LayoutFor(records []models.Record) Layout {
+	slots := 0
+	for _, record := range records {
+		slots = max(slots, len(record.Salary))
+	}
+	return NewLayout(slots)
+}
+
+// Fits reports whether the layout has a slot for every company of record.
+func (l Layout) Fits(record models.Record) bool {
+	return len(record.Salary) <= l.companySlots
+}
+
+// Header returns the column names.
+func (l Layout) Header() []string {
+	header := make([]string, len(l.columns))
+	for i, column := range l.columns {
+		header[i] = column.name
+	}
+	return header
+}
+
+// Row flattens a record into one cell per column.
+func (l Layout) Row(record models.Record) []string {
+	row := make([]string, len(l.columns))
+	for i, column := range l.columns {
+		row[i] = column.get(&record)
+	}
+	return row
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
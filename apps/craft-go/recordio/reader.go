@@ -0,0 +1,212 @@
+package recordio
+
+import (
+	"bufio"
+	"bytes"
+	"craft-fusion/craft-go/models"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// maxCompanySlots bounds the salary[N] columns a file may declare.
+const maxCompanySlots = 100
+
+// maxLineSize bounds one NDJSON line.
+const maxLineSize = 1 << 20
+
+var companyColumn = regexp.MustCompile(`^salary\[(\d+)\]\.(\w+)$`)
+
+// RowError reports a line of an input file that could not be decoded into a
+// record. Reading continues with the next line.
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Reader decodes records one at a time. Read returns a *RowError for a line
+// that does not decode, io.EOF after the last record, and any other error
+// when the input itself cannot be read.
+type Reader interface {
+	Read() (models.Record, error)
+	// Line returns the line number of the record last read, counting from 1.
+	Line() int
+}
+
+// NewReader returns a Reader for format. CSV input starts with a header row
+// naming columns of the flat layout in any order and subset; NDJSON input
+// holds one record object per line.
+func NewReader(r io.Reader, format string) (Reader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+		return &ndjsonReader{scanner: scanner}, nil
+	}
+	return nil, fmt.Errorf("unknown import format %q", format)
+}
+
+// ParseHeader resolves column names to a Layout. Every name must be a column
+// of the flat layout; salary[N] columns may use any slot below 100.
+func ParseHeader(header []string) (Layout, error) {
+	known := make(map[string]column, len(recordColumns))
+	for _, column := range recordColumns {
+		known[column.name] = column
+	}
+
+	layout := Layout{columns: make([]column, len(header))}
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		if seen[name] {
+			return Layout{}, fmt.Errorf("duplicate column %q", name)
+		}
+		seen[name] = true
+		if column, ok := known[name]; ok {
+			layout.columns[i] = column
+			continue
+		}
+		match := companyColumn.FindStringSubmatch(name)
+		if match == nil {
+			return Layout{}, fmt.Errorf("unknown column %q", name)
+		}
+		slot, err := strconv.Atoi(match[1])
+		if err != nil || slot >= maxCompanySlots {
+			return Layout{}, fmt.Errorf("column %q exceeds %d company slots", name, maxCompanySlots)
+		}
+		found := false
+		for _, column := range companyColumns(slot) {
+			if column.name == name {
+				layout.columns[i], found = column, true
+			}
+		}
+		if !found {
+			return Layout{}, fmt.Errorf("unknown column %q", name)
+		}
+	}
+	return layout, nil
+}
+
+// Setter returns the function that stores a cell value into the field of the
+// named column, parsing it the same way as an imported cell.
+func Setter(name string) (func(record *models.Record, value string) error, error) {
+	layout, err := ParseHeader([]string{name})
+	if err != nil {
+		return nil, err
+	}
+	return layout.columns[0].set, nil
+}
+
+// Record builds a record from one row of cells. Company slots whose cells are
+// all empty are dropped, leaving an empty rather than nil salary list.
+func (l Layout) Record(row []string) (models.Record, error) {
+	if len(row) != len(l.columns) {
+		return models.Record{}, fmt.Errorf("expected %d columns, got %d", len(l.columns), len(row))
+	}
+	var record models.Record
+	var errs []error
+	for i, column := range l.columns {
+		if err := column.set(&record, row[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return models.Record{}, errors.Join(errs...)
+	}
+
+	companies := make([]models.Company, 0, len(record.Salary))
+	for _, company := range record.Salary {
+		if company != (models.Company{}) {
+			companies = append(companies, company)
+		}
+	}
+	record.Salary = companies
+	return record, nil
+}
+
+type csvReader struct {
+	csv    *csv.Reader
+	layout Layout
+	line   int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("missing CSV header row")
+	}
+	if err != nil {
+		return nil, err
+	}
+	layout, err := ParseHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	return &csvReader{csv: reader, layout: layout, line: 1}, nil
+}
+
+func (r *csvReader) Read() (models.Record, error) {
+	row, err := r.csv.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		r.line = parseErr.StartLine
+		return models.Record{}, &RowError{Line: r.line, Err: parseErr.Err}
+	}
+	if err != nil {
+		return models.Record{}, err
+	}
+	r.line, _ = r.csv.FieldPos(0)
+	record, err := r.layout.Record(row)
+	if err != nil {
+		return models.Record{}, &RowError{Line: r.line, Err: err}
+	}
+	return record, nil
+}
+
+func (r *csvReader) Line() int {
+	return r.line
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (r *ndjsonReader) Read() (models.Record, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var record models.Record
+		if err := json.Unmarshal(line, &record); err != nil {
+			return models.Record{}, &RowError{Line: r.line, Err: err}
+		}
+		return record, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return models.Record{}, err
+	}
+	return models.Record{}, io.EOF
+}
+
+func (r *ndjsonReader) Line() int {
+	return r.line
+}
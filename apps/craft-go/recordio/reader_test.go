@@ -0,0 +1,105 @@
+package recordio
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"craft-fusion/craft-go/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readAll collects the records and row errors of an input.
+func readAll(t *testing.T, reader Reader) ([]models.Record, []*RowError) {
+	t.Helper()
+	var records []models.Record
+	var rowErrs []*RowError
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, rowErrs
+		}
+		var rowErr *RowError
+		if errors.As(err, &rowErr) {
+			rowErrs = append(rowErrs, rowErr)
+			continue
+		}
+		require.NoError(t, err)
+		records = append(records, record)
+	}
+}
+
+func TestExportedFilesReadBack(t *testing.T) {
+	t.Parallel()
+	for _, format := range []string{FormatCSV, FormatNDJSON} {
+		t.Run(format, func(t *testing.T) {
+			want := exportTestRecords()
+			reader, err := NewReader(strings.NewReader(string(writeAll(t, format, want))), format)
+			require.NoError(t, err)
+			records, rowErrs := readAll(t, reader)
+			assert.Empty(t, rowErrs)
+			if format == FormatCSV {
+				// Flat files cannot tell a missing salary list from an empty one.
+				want[1].Salary = []models.Company{}
+			}
+			assert.Equal(t, want, records)
+		})
+	}
+}
+
+func TestCSVReaderReportsBadRows(t *testing.T) {
+	t.Parallel()
+	input := "lastName,firstName,salary[2].annualSalary,phone.hasExtension\n" +
+		"Smith,Ann,1000,true\n" +
+		"Jones,Bob,lots,maybe\n" +
+		"Lee,Cy\n" +
+		"\"Unclosed,Di,,\n"
+	reader, err := NewReader(strings.NewReader(input), FormatCSV)
+	require.NoError(t, err)
+	records, rowErrs := readAll(t, reader)
+
+	require.Len(t, records, 1)
+	assert.Equal(t, "Smith", records[0].LastName)
+	assert.Equal(t, []models.Company{{AnnualSalary: 1000}}, records[0].Salary)
+	require.Len(t, rowErrs, 3)
+	assert.Equal(t, 3, rowErrs[0].Line)
+	assert.ErrorContains(t, rowErrs[0], "salary[2].annualSalary must be a number")
+	assert.ErrorContains(t, rowErrs[0], "phone.hasExtension must be true or false")
+	assert.Equal(t, 4, rowErrs[1].Line)
+	assert.ErrorContains(t, rowErrs[1], "expected 4 columns, got 2")
+	assert.Equal(t, 5, rowErrs[2].Line)
+}
+
+func TestParseHeaderRejectsUnknownColumns(t *testing.T) {
+	t.Parallel()
+	for _, header := range [][]string{
+		{"UID", "avatar"},
+		{"UID", "UID"},
+		{"salary[0].bonus"},
+		{"salary[100].companyName"},
+	} {
+		_, err := ParseHeader(header)
+		assert.Error(t, err, header)
+	}
+	_, err := NewReader(strings.NewReader(""), FormatCSV)
+	assert.ErrorContains(t, err, "missing CSV header row")
+	_, err = NewReader(strings.NewReader(""), FormatXLSX)
+	assert.Error(t, err)
+}
+
+func TestNDJSONReaderSkipsBlankLines(t *testing.T) {
+	t.Parallel()
+	input := `{"UID":"1","firstName":"Ann","lastName":"Smith"}` + "\n\n" + `{"UID":` + "\n" + `{"UID":"2"}`
+	reader, err := NewReader(strings.NewReader(input), FormatNDJSON)
+	require.NoError(t, err)
+	records, rowErrs := readAll(t, reader)
+
+	require.Len(t, records, 2)
+	assert.Equal(t, "2", records[1].UID)
+	assert.Equal(t, 4, reader.Line())
+	require.Len(t, rowErrs, 1)
+	assert.Equal(t, 3, rowErrs[0].Line)
+}
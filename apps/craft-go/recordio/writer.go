@@ -0,0 +1,91 @@
+package recordio
+
+import (
+	"craft-fusion/craft-go/models"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Export formats.
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatXLSX   = "xlsx"
+)
+
+// Writer encodes records one at a time. CSV and XLSX writers return
+// ErrLayoutTooNarrow for a record their layout cannot hold. Flush pushes buffered output to the
+// underlying writer; Close finishes the file and must be called once after
+// the last record.
+type Writer interface {
+	Write(record models.Record) error
+	Flush() error
+	Close() error
+}
+
+// ContentType returns the media type of an export format.
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return ""
+}
+
+// NewWriter returns a Writer for format. CSV and XLSX flatten records using
+// layout; NDJSON keeps the nested record shape, one record per line.
+func NewWriter(w io.Writer, format string, layout Layout) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, layout)
+	case FormatNDJSON:
+		return &ndjsonWriter{encoder: json.NewEncoder(w)}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w, layout)
+	}
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
+
+type csvWriter struct {
+	csv    *csv.Writer
+	layout Layout
+}
+
+func newCSVWriter(w io.Writer, layout Layout) (*csvWriter, error) {
+	writer := &csvWriter{csv: csv.NewWriter(w), layout: layout}
+	return writer, writer.csv.Write(layout.Header())
+}
+
+func (w *csvWriter) Write(record models.Record) error {
+	if !w.layout.Fits(record) {
+		return ErrLayoutTooNarrow
+	}
+	return w.csv.Write(w.layout.Row(record))
+}
+
+func (w *csvWriter) Flush() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+func (w *csvWriter) Close() error {
+	return w.Flush()
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonWriter) Write(record models.Record) error {
+	return w.encoder.Encode(record)
+}
+
+func (w *ndjsonWriter) Flush() error { return nil }
+
+func (w *ndjsonWriter) Close() error { return nil }
@@ -0,0 +1,159 @@
+package recordio
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"craft-fusion/craft-go/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportTestRecords() []models.Record {
+	position := "Engineer"
+	hasExtension := true
+	return []models.Record{
+		{
+			UID: "1", FirstName: "Ann", LastName: "Smith, Jr.", TotalHouseholdIncome: 120000.5,
+			Address: models.Address{Street: "1 Main St", City: "Denver", State: "Colorado", Zipcode: "80202"},
+			Phone:   models.Phone{Number: "555-0100", HasExtension: &hasExtension},
+			Salary: []models.Company{
+				{UID: "c1", CompanyName: "Acme", CompanyPosition: &position, AnnualSalary: 70000},
+				{UID: "c2", CompanyName: "Initech <R&D>", AnnualSalary: 50000},
+			},
+		},
+		{UID: "2", FirstName: "Bob", LastName: "Jones"},
+	}
+}
+
+func writeAll(t *testing.T, format string, records []models.Record) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, format, LayoutFor(records))
+	require.NoError(t, err)
+	for _, record := range records {
+		require.NoError(t, writer.Write(record))
+	}
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestLayoutFlattensNestedFields(t *testing.T) {
+	t.Parallel()
+	records := exportTestRecords()
+	layout := LayoutFor(records)
+	header := layout.Header()
+
+	require.Len(t, header, len(recordColumns)+12)
+	assert.Equal(t, "salary[1].currency", header[len(header)-1])
+	cells := map[string]string{}
+	for i, cell := range layout.Row(records[0]) {
+		cells[header[i]] = cell
+	}
+	assert.Equal(t, "Denver", cells["address.city"])
+	assert.Equal(t, "120000.5", cells["totalHouseholdIncome"])
+	assert.Equal(t, "true", cells["phone.hasExtension"])
+	assert.Equal(t, "", cells["phone.extension"])
+	assert.Equal(t, "Engineer", cells["salary[0].companyPosition"])
+	assert.Equal(t, "Initech <R&D>", cells["salary[1].companyName"])
+
+	second := layout.Row(records[1])
+	assert.Equal(t, "", second[len(second)-1])
+}
+
+func TestCSVWriter(t *testing.T) {
+	t.Parallel()
+	rows, err := csv.NewReader(bytes.NewReader(writeAll(t, FormatCSV, exportTestRecords()))).ReadAll()
+	require.NoError(t, err)
+
+	require.Len(t, rows, 3)
+	assert.Equal(t, "UID", rows[0][0])
+	assert.Equal(t, "Smith, Jr.", rows[1][3])
+	assert.Equal(t, "Bob", rows[2][2])
+}
+
+func TestNDJSONWriterKeepsNestedShape(t *testing.T) {
+	t.Parallel()
+	lines := strings.Split(strings.TrimSpace(string(writeAll(t, FormatNDJSON, exportTestRecords()))), "\n")
+	require.Len(t, lines, 2)
+	var record models.Record
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, exportTestRecords()[0], record)
+}
+
+func TestXLSXWriterProducesWorkbook(t *testing.T) {
+	t.Parallel()
+	data := writeAll(t, FormatXLSX, exportTestRecords())
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	parts := map[string]*zip.File{}
+	for _, file := range archive.File {
+		parts[file.Name] = file
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels"} {
+		assert.Contains(t, parts, name)
+	}
+	require.Contains(t, parts, "xl/worksheets/sheet1.xml")
+	sheet, err := parts["xl/worksheets/sheet1.xml"].Open()
+	require.NoError(t, err)
+	body, err := io.ReadAll(sheet)
+	require.NoError(t, err)
+
+	var worksheet struct {
+		Rows []struct {
+			Ref   string `xml:"r,attr"`
+			Cells []struct {
+				Ref    string `xml:"r,attr"`
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	require.NoError(t, xml.Unmarshal(body, &worksheet))
+	require.Len(t, worksheet.Rows, 3)
+	assert.Equal(t, "UID", worksheet.Rows[0].Cells[0].Inline)
+
+	cells := map[string]string{}
+	for _, cell := range worksheet.Rows[1].Cells {
+		cells[cell.Ref] = cell.Value + cell.Inline
+	}
+	assert.Equal(t, "Smith, Jr.", cells["D2"])
+	assert.Equal(t, "120000.5", cells["H2"])
+	assert.Equal(t, "Initech <R&D>", cells[columnRef(len(recordColumns)+8)+"2"])
+}
+
+func TestColumnRef(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "A", columnRef(0))
+	assert.Equal(t, "Z", columnRef(25))
+	assert.Equal(t, "AA", columnRef(26))
+	assert.Equal(t, "AZ", columnRef(51))
+	assert.Equal(t, "BA", columnRef(52))
+}
+
+func TestNewWriterRejectsUnknownFormat(t *testing.T) {
+	t.Parallel()
+	_, err := NewWriter(io.Discard, "pdf", NewLayout(0))
+	assert.Error(t, err)
+	assert.Empty(t, ContentType("pdf"))
+}
+
+func TestFlatWritersRejectRecordsWiderThanLayout(t *testing.T) {
+	t.Parallel()
+	records := exportTestRecords()
+	for _, format := range []string{FormatCSV, FormatXLSX} {
+		writer, err := NewWriter(io.Discard, format, LayoutFor(records[1:]))
+		require.NoError(t, err)
+		assert.NoError(t, writer.Write(records[1]), format)
+		assert.ErrorIs(t, writer.Write(records[0]), ErrLayoutTooNarrow, format)
+	}
+}
@@ -0,0 +1,129 @@
+package recordio
+
+import (
+	"archive/zip"
+	"bufio"
+	"craft-fusion/craft-go/models"
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+// The static parts of a single-sheet workbook. The worksheet itself is
+// streamed row by row, so exports never hold the whole file in memory.
+var xlsxParts = []struct{ name, body string }{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Records" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+const (
+	xlsxSheetStart = xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>` +
+		`<sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// xlsxWriter streams records into the first worksheet of an XLSX workbook,
+// with the column names as a frozen header row.
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	layout  Layout
+	refs    []string
+	row     int
+}
+
+func newXLSXWriter(w io.Writer, layout Layout) (*xlsxWriter, error) {
+	archive := zip.NewWriter(w)
+	for _, part := range xlsxParts {
+		file, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(file, part.body); err != nil {
+			return nil, err
+		}
+	}
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	writer := &xlsxWriter{archive: archive, sheet: bufio.NewWriter(sheet), layout: layout}
+	writer.refs = make([]string, len(layout.columns))
+	for i := range layout.columns {
+		writer.refs[i] = columnRef(i)
+	}
+	writer.sheet.WriteString(xlsxSheetStart)
+	return writer, writer.writeRow(layout.Header(), nil)
+}
+
+func (w *xlsxWriter) Write(record models.Record) error {
+	if !w.layout.Fits(record) {
+		return ErrLayoutTooNarrow
+	}
+	return w.writeRow(w.layout.Row(record), w.layout.columns)
+}
+
+// writeRow writes one sheet row. Cells of numeric columns are typed as
+// numbers; empty cells are omitted.
+func (w *xlsxWriter) writeRow(cells []string, columns []column) error {
+	w.row++
+	row := strconv.Itoa(w.row)
+	w.sheet.WriteString(`<row r="` + row + `">`)
+	for i, cell := range cells {
+		if cell == "" {
+			continue
+		}
+		ref := w.refs[i] + row
+		if columns != nil && columns[i].numeric {
+			w.sheet.WriteString(`<c r="` + ref + `"><v>` + cell + `</v></c>`)
+			continue
+		}
+		w.sheet.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">`)
+		if err := xml.EscapeText(w.sheet, []byte(cell)); err != nil {
+			return err
+		}
+		w.sheet.WriteString(`</t></is></c>`)
+	}
+	_, err := w.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (w *xlsxWriter) Flush() error {
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.archive.Flush()
+}
+
+func (w *xlsxWriter) Close() error {
+	w.sheet.WriteString(xlsxSheetEnd)
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.archive.Close()
+}
+
+// columnRef returns the spreadsheet letters of a 0-based column index:
+// A..Z, AA..AZ and so on.
+func columnRef(index int) string {
+	ref := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		ref = string(rune('A'+(index-1)%26)) + ref
+	}
+	return ref
+}
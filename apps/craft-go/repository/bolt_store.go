@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"craft-fusion/craft-go/models"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const boltFileName = "records.db"
+
+var (
+	// recordsBucket maps an insertion sequence number, big-endian so keys
+	// sort in insertion order, to a record.
+	recordsBucket = []byte("ordered-records")
+	// sequencesBucket maps a record UID to its key in recordsBucket.
+	sequencesBucket = []byte("record-sequences")
+	// legacyRecordsBucket keyed records by UID before insertion order was
+	// persisted; it is migrated on open.
+	legacyRecordsBucket = []byte("records")
+)
+
+// BoltStore is a file-backed RecordStore built on bbolt. Records are written
+// through to disk and served from an in-memory copy that is reloaded on open,
+// so a restarted server keeps the UIDs its clients already hold, in the same
+// order.
+type BoltStore struct {
+	// writeMu keeps the on-disk data and the in-memory copy in the same order.
+	writeMu sync.Mutex
+	db      *bolt.DB
+	cache   *MemoryStore
+}
+
+// NewBoltStore opens (or creates) the record database inside dataDir and loads
+// any records persisted by a previous run.
+func NewBoltStore(dataDir string) (*BoltStore, error) {
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		return nil, fmt.Errorf("create data directory: %w", err)
+	}
+
+	db, err := bolt.Open(filepath.Join(dataDir, boltFileName), 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open record database: %w", err)
+	}
+
+	store := &BoltStore{db: db, cache: NewMemoryStore()}
+	if err := store.load(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *BoltStore) load() error {
+	var records []models.Record
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		if err := createBuckets(tx); err != nil {
+			return err
+		}
+		if err := migrateLegacyRecords(tx); err != nil {
+			return err
+		}
+		bucket := tx.Bucket(recordsBucket)
+		records = make([]models.Record, 0, bucket.Stats().KeyN)
+		return bucket.ForEach(func(_, value []byte) error {
+			var record models.Record
+			if err := json.Unmarshal(value, &record); err != nil {
+				return err
+			}
+			records = append(records, record)
+			return nil
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("load records: %w", err)
+	}
+	return s.cache.Replace(records)
+}
+
+// Close releases the underlying database file.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// Get returns a record by UID.
+func (s *BoltStore) Get(uid string) (models.Record, error) {
+	return s.cache.Get(uid)
+}
+
+// FindBy returns the records whose indexed field matches value.
+func (s *BoltStore) FindBy(index Index, value string) []models.Record {
+	return s.cache.FindBy(index, value)
+}
+
+// List returns a snapshot of every stored record.
+func (s *BoltStore) List() []models.Record {
+	return s.cache.List()
+}
+
+// Scan visits the stored records in order without copying the dataset.
+func (s *BoltStore) Scan(visit func(models.Record) bool) {
+	s.cache.Scan(visit)
+}
+
+// Count returns the number of stored records.
+func (s *BoltStore) Count() int {
+	return s.cache.Count()
+}
+
+// Version returns the mutation counter of the dataset since the store opened.
+func (s *BoltStore) Version() uint64 {
+	return s.cache.Version()
+}
+
+// Search runs a full-text query against the cached dataset.
+func (s *BoltStore) Search(query string, limit int) ([]SearchHit, int) {
+	return s.cache.Search(query, limit)
+}
+
+// Put persists a record, replacing any existing record with the same UID.
+func (s *BoltStore) Put(record models.Record) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		return putRecord(tx, record.UID, value)
+	})
+	if err != nil {
+		return err
+	}
+	return s.cache.Put(record)
+}
+
+// PutMany persists the records in a single transaction.
+func (s *BoltStore) PutMany(records []models.Record) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return putRecords(tx, records)
+	})
+	if err != nil {
+		return err
+	}
+	return s.cache.PutMany(records)
+}
+
+// Delete removes a persisted record by UID.
+func (s *BoltStore) Delete(uid string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if _, err := s.cache.Get(uid); err != nil {
+		return err
+	}
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		sequences := tx.Bucket(sequencesBucket)
+		if key := sequences.Get([]byte(uid)); key != nil {
+			if err := tx.Bucket(recordsBucket).Delete(key); err != nil {
+				return err
+			}
+		}
+		return sequences.Delete([]byte(uid))
+	})
+	if err != nil {
+		return err
+	}
+	return s.cache.Delete(uid)
+}
+
+// Replace atomically swaps the persisted dataset for the given records.
+func (s *BoltStore) Replace(records []models.Record) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{recordsBucket, sequencesBucket} {
+			if err := tx.DeleteBucket(name); err != nil {
+				return err
+			}
+		}
+		if err := createBuckets(tx); err != nil {
+			return err
+		}
+		return putRecords(tx, records)
+	})
+	if err != nil {
+		return err
+	}
+	return s.cache.Replace(records)
+}
+
+func createBuckets(tx *bolt.Tx) error {
+	for _, name := range [][]byte{recordsBucket, sequencesBucket} {
+		if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateLegacyRecords moves records stored by UID into the ordered buckets,
+// in UID order, and drops the old bucket.
+func migrateLegacyRecords(tx *bolt.Tx) error {
+	legacy := tx.Bucket(legacyRecordsBucket)
+	if legacy == nil {
+		return nil
+	}
+	err := legacy.ForEach(func(uid, value []byte) error {
+		return putRecord(tx, string(uid), value)
+	})
+	if err != nil {
+		return err
+	}
+	return tx.DeleteBucket(legacyRecordsBucket)
+}
+
+func putRecords(tx *bolt.Tx, records []models.Record) error {
+	for _, record := range records {
+		value, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		if err := putRecord(tx, record.UID, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// putRecord stores the encoded record under its existing sequence key, so a
+// replaced record keeps its position, or under the next one.
+func putRecord(tx *bolt.Tx, uid string, value []byte) error {
+	records, sequences := tx.Bucket(recordsBucket), tx.Bucket(sequencesBucket)
+	key := sequences.Get([]byte(uid))
+	if key == nil {
+		sequence, err := records.NextSequence()
+		if err != nil {
+			return err
+		}
+		key = binary.BigEndian.AppendUint64(nil, sequence)
+		if err := sequences.Put([]byte(uid), key); err != nil {
+			return err
+		}
+	} else {
+		key = append([]byte(nil), key...)
+	}
+	return records.Put(key, value)
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"craft-fusion/craft-go/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestBoltStoreReloadsPersistedRecords(t *testing.T) {
+	dataDir := t.TempDir()
+	store, err := NewBoltStore(dataDir)
+	require.NoError(t, err)
+
+	generated := GenerateMockRecords(3, 0)
+	require.NoError(t, store.Replace(append([]models.Record(nil), generated...)))
+	updated := generated[0]
+	updated.LastName = "Persisted"
+	require.NoError(t, store.Put(updated))
+	require.NoError(t, store.Delete(generated[2].UID))
+	added := GenerateMockRecords(1, 7)[0]
+	renamed := generated[1]
+	renamed.FirstName = "Merged"
+	require.NoError(t, store.PutMany([]models.Record{added, renamed}))
+	persisted := store.List()
+	require.NoError(t, store.Close())
+
+	reopened, err := NewBoltStore(dataDir)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	assert.Equal(t, 3, reopened.Count())
+	assert.Equal(t, persisted, reopened.List(), "reloaded records keep their insertion order")
+	found, err := reopened.Get(updated.UID)
+	require.NoError(t, err)
+	assert.Equal(t, updated, found)
+	found, err = reopened.Get(renamed.UID)
+	require.NoError(t, err)
+	assert.Equal(t, "Merged", found.FirstName)
+	_, err = reopened.Get(added.UID)
+	require.NoError(t, err)
+	_, err = reopened.Get(generated[2].UID)
+	assert.ErrorIs(t, err, ErrRecordNotFound)
+	assert.ErrorIs(t, reopened.Delete(generated[2].UID), ErrRecordNotFound)
+}
+
+func TestBoltStoreMigratesRecordsKeyedByUID(t *testing.T) {
+	dataDir := t.TempDir()
+	db, err := bolt.Open(filepath.Join(dataDir, boltFileName), 0o600, nil)
+	require.NoError(t, err)
+	records := GenerateMockRecords(3, 5)
+	require.NoError(t, db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucket(legacyRecordsBucket)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			value, err := json.Marshal(record)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put([]byte(record.UID), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	require.NoError(t, db.Close())
+
+	store, err := NewBoltStore(dataDir)
+	require.NoError(t, err)
+	assert.Equal(t, 3, store.Count())
+	added := GenerateMockRecords(1, 9)[0]
+	require.NoError(t, store.Put(added))
+	migrated := store.List()
+	assert.Equal(t, added, migrated[3], "records added after the migration come last")
+	require.NoError(t, store.Close())
+
+	reopened, err := NewBoltStore(dataDir)
+	require.NoError(t, err)
+	defer reopened.Close()
+	assert.Equal(t, migrated, reopened.List())
+}
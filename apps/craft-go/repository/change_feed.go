@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"craft-fusion/craft-go/models"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Change types published by an observed store.
+const (
+	ChangeCreated     = "created"
+	ChangeUpdated     = "updated"
+	ChangeDeleted     = "deleted"
+	ChangeRegenerated = "regenerated"
+	ChangeMerged      = "merged"
+)
+
+// Change describes one mutation of the dataset.
+type Change struct {
+	Type string `json:"type" example:"updated" enums:"created,updated,deleted,regenerated,merged"`
+	// UID and Record identify the record changed: the stored record for
+	// created and updated, the removed one for deleted. Both are empty for
+	// regenerated, which replaces the whole dataset, and for merged, which
+	// upserts many records at once.
+	UID    string         `json:"uid,omitempty" example:"9a5e0b1e-3f6d-4c2e-8d1a-5b7c9e2f4a60"`
+	Record *models.Record `json:"record,omitempty"`
+	// Count is the dataset size after a regeneration, or the number of
+	// records upserted by a merge.
+	Count int `json:"count,omitempty" example:"1000"`
+	// Version is the store version after the change; see RecordStore.Version.
+	Version   uint64    `json:"version" example:"12"`
+	Timestamp time.Time `json:"timestamp"`
+
+	// previousState is the state of an updated record before the change, so
+	// subscribers to either state learn the record moved.
+	previousState string
+}
+
+// ChangeFilter selects the changes a subscription receives. A change matches
+// when its record has one of UIDs or, ignoring case, one of States. An empty
+// filter matches every change, and regenerations and merges match every
+// filter.
+type ChangeFilter struct {
+	States []string `json:"states,omitempty" example:"Colorado"`
+	UIDs   []string `json:"uids,omitempty"`
+}
+
+func (f ChangeFilter) matches(change Change) bool {
+	if change.Type == ChangeRegenerated || change.Type == ChangeMerged || (len(f.States) == 0 && len(f.UIDs) == 0) {
+		return true
+	}
+	for _, uid := range f.UIDs {
+		if uid == change.UID {
+			return true
+		}
+	}
+	for _, state := range f.States {
+		if (change.Record != nil && strings.EqualFold(state, change.Record.Address.State)) ||
+			(change.previousState != "" && strings.EqualFold(state, change.previousState)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangeFeed fans changes out to subscriptions. Publishing never blocks: a
+// subscription whose buffer is full loses the change and counts it as
+// dropped instead of slowing down writers.
+type ChangeFeed struct {
+	mu            sync.RWMutex
+	subscriptions map[*Subscription]struct{}
+}
+
+// NewChangeFeed returns a feed without subscriptions.
+func NewChangeFeed() *ChangeFeed {
+	return &ChangeFeed{subscriptions: make(map[*Subscription]struct{})}
+}
+
+// Subscription receives the changes matching its filter.
+type Subscription struct {
+	feed    *ChangeFeed
+	changes chan Change
+	filter  atomic.Pointer[ChangeFilter]
+	dropped atomic.Uint64
+	// signal wakes the consumer when changes were dropped.
+	signal chan struct{}
+}
+
+// Subscribe returns a subscription buffering up to size changes. Close it
+// when done.
+func (f *ChangeFeed) Subscribe(filter ChangeFilter, size int) *Subscription {
+	subscription := &Subscription{
+		feed:    f,
+		changes: make(chan Change, size),
+		signal:  make(chan struct{}, 1),
+	}
+	subscription.filter.Store(&filter)
+	f.mu.Lock()
+	f.subscriptions[subscription] = struct{}{}
+	f.mu.Unlock()
+	return subscription
+}
+
+// Publish delivers change to every matching subscription.
+func (f *ChangeFeed) Publish(change Change) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for subscription := range f.subscriptions {
+		if !subscription.filter.Load().matches(change) {
+			continue
+		}
+		select {
+		case subscription.changes <- change:
+		default:
+			subscription.dropped.Add(1)
+			select {
+			case subscription.signal <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// Changes returns the channel of matching changes. It is never closed.
+func (s *Subscription) Changes() <-chan Change {
+	return s.changes
+}
+
+// Dropped returns a channel that receives after changes were dropped; call
+// TakeDropped to learn how many.
+func (s *Subscription) Dropped() <-chan struct{} {
+	return s.signal
+}
+
+// TakeDropped returns the number of changes dropped since the last call.
+func (s *Subscription) TakeDropped() uint64 {
+	return s.dropped.Swap(0)
+}
+
+// SetFilter replaces the filter for changes published from now on.
+func (s *Subscription) SetFilter(filter ChangeFilter) {
+	s.filter.Store(&filter)
+}
+
+// Filter returns the current filter.
+func (s *Subscription) Filter() ChangeFilter {
+	return *s.filter.Load()
+}
+
+// Close stops delivery to the subscription.
+func (s *Subscription) Close() {
+	s.feed.mu.Lock()
+	delete(s.feed.subscriptions, s)
+	s.feed.mu.Unlock()
+}
+
+// ObservedStore is a RecordStore that publishes every successful mutation of
+// the wrapped store to a ChangeFeed. Mutations are serialized and published
+// before the next one starts, so subscribers see changes in version order
+// and each change carries the version it produced.
+type ObservedStore struct {
+	RecordStore
+	feed *ChangeFeed
+	// mu is held across each mutation and its publication.
+	mu sync.Mutex
+}
+
+// Observe wraps store so its mutations are published to feed.
+func Observe(store RecordStore, feed *ChangeFeed) *ObservedStore {
+	return &ObservedStore{RecordStore: store, feed: feed}
+}
+
+// Feed returns the feed changes are published to.
+func (s *ObservedStore) Feed() *ChangeFeed {
+	return s.feed
+}
+
+func (s *ObservedStore) publish(changeType string, record *models.Record, previous *models.Record) {
+	change := Change{Type: changeType, Version: s.Version(), Timestamp: time.Now().UTC()}
+	if record != nil {
+		change.UID, change.Record = record.UID, record
+	}
+	if previous != nil {
+		change.previousState = previous.Address.State
+	}
+	s.feed.Publish(change)
+}
+
+// Put stores record and publishes it as created or updated.
+func (s *ObservedStore) Put(record models.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, found := s.previous(record.UID)
+	if err := s.RecordStore.Put(record); err != nil {
+		return err
+	}
+	s.publishPut(record, previous, found)
+	return nil
+}
+
+// PutMany stores records and publishes a single merge, so a large import
+// does not flood subscribers with one change per record.
+func (s *ObservedStore) PutMany(records []models.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.RecordStore.PutMany(records); err != nil {
+		return err
+	}
+	s.feed.Publish(Change{Type: ChangeMerged, Count: len(records), Version: s.Version(), Timestamp: time.Now().UTC()})
+	return nil
+}
+
+func (s *ObservedStore) previous(uid string) (models.Record, bool) {
+	record, err := s.RecordStore.Get(uid)
+	return record, err == nil
+}
+
+func (s *ObservedStore) publishPut(record, previous models.Record, found bool) {
+	if found {
+		s.publish(ChangeUpdated, &record, &previous)
+		return
+	}
+	s.publish(ChangeCreated, &record, nil)
+}
+
+// Delete removes the record and publishes it as deleted.
+func (s *ObservedStore) Delete(uid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// A successful Delete means the record existed, and mu keeps it from
+	// changing in between.
+	record, _ := s.previous(uid)
+	if err := s.RecordStore.Delete(uid); err != nil {
+		return err
+	}
+	s.publish(ChangeDeleted, &record, nil)
+	return nil
+}
+
+// Replace swaps the dataset and publishes a regeneration.
+func (s *ObservedStore) Replace(records []models.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.RecordStore.Replace(records); err != nil {
+		return err
+	}
+	s.feed.Publish(Change{Type: ChangeRegenerated, Count: s.Count(), Version: s.Version(), Timestamp: time.Now().UTC()})
+	return nil
+}
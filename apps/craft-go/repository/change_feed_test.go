@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"craft-fusion/craft-go/models"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receive(t *testing.T, subscription *Subscription) Change {
+	t.Helper()
+	select {
+	case change := <-subscription.Changes():
+		return change
+	default:
+		t.Fatal("no change published")
+		return Change{}
+	}
+}
+
+func TestObservedStorePublishesMutations(t *testing.T) {
+	t.Parallel()
+	feed := NewChangeFeed()
+	store := Observe(NewMemoryStore(), feed)
+	all := feed.Subscribe(ChangeFilter{}, 16)
+	defer all.Close()
+
+	require.NoError(t, store.Replace(GenerateMockRecords(3, 1)))
+	regenerated := receive(t, all)
+	assert.Equal(t, ChangeRegenerated, regenerated.Type)
+	assert.Equal(t, 3, regenerated.Count)
+	assert.Equal(t, store.Version(), regenerated.Version)
+
+	record := GenerateMockRecords(1, 2)[0]
+	require.NoError(t, store.Put(record))
+	created := receive(t, all)
+	assert.Equal(t, ChangeCreated, created.Type)
+	assert.Equal(t, record.UID, created.UID)
+	assert.Equal(t, &record, created.Record)
+
+	record.LastName = "Updated"
+	require.NoError(t, store.Put(record))
+	assert.Equal(t, ChangeUpdated, receive(t, all).Type)
+
+	require.NoError(t, store.PutMany([]models.Record{record, GenerateMockRecords(1, 3)[0]}))
+	merged := receive(t, all)
+	assert.Equal(t, ChangeMerged, merged.Type)
+	assert.Equal(t, 2, merged.Count)
+	assert.Empty(t, all.Changes(), "a merge publishes one change")
+
+	require.NoError(t, store.Delete(record.UID))
+	deleted := receive(t, all)
+	assert.Equal(t, ChangeDeleted, deleted.Type)
+	assert.Equal(t, "Updated", deleted.Record.LastName)
+
+	assert.ErrorIs(t, store.Delete(record.UID), ErrRecordNotFound)
+	assert.Empty(t, all.Changes(), "failed writes publish nothing")
+}
+
+// yieldingStore pauses after each Put so concurrent writers interleave
+// between storing a record and publishing it.
+type yieldingStore struct {
+	RecordStore
+}
+
+func (s yieldingStore) Put(record models.Record) error {
+	err := s.RecordStore.Put(record)
+	time.Sleep(10 * time.Microsecond)
+	return err
+}
+
+func TestObservedStorePublishesInVersionOrder(t *testing.T) {
+	t.Parallel()
+	feed := NewChangeFeed()
+	store := Observe(yieldingStore{NewMemoryStore()}, feed)
+	const writers, writes = 8, 50
+	all := feed.Subscribe(ChangeFilter{}, writers*writes)
+	defer all.Close()
+
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, record := range GenerateMockRecords(writes, int64(w+1)) {
+				assert.NoError(t, store.Put(record))
+			}
+		}()
+	}
+	wg.Wait()
+
+	var last uint64
+	for range writers * writes {
+		change := receive(t, all)
+		assert.Greater(t, change.Version, last)
+		last = change.Version
+	}
+	assert.Equal(t, store.Version(), last)
+}
+
+func TestChangeFeedFiltersByStateAndUID(t *testing.T) {
+	t.Parallel()
+	feed := NewChangeFeed()
+	store := Observe(NewMemoryStore(), feed)
+	records := GenerateMockRecords(2, 3)
+	records[0].Address.State, records[1].Address.State = "Colorado", "Vermont"
+	require.NoError(t, store.Replace(append([]models.Record(nil), records...)))
+
+	colorado := feed.Subscribe(ChangeFilter{States: []string{"colorado"}}, 16)
+	defer colorado.Close()
+	byUID := feed.Subscribe(ChangeFilter{UIDs: []string{records[1].UID}}, 16)
+	defer byUID.Close()
+
+	require.NoError(t, store.Put(records[1]))
+	assert.Empty(t, colorado.Changes())
+	assert.Equal(t, records[1].UID, receive(t, byUID).UID)
+
+	moved := records[0]
+	moved.Address.State = "Vermont"
+	require.NoError(t, store.Put(moved))
+	assert.Equal(t, "Vermont", receive(t, colorado).Record.Address.State, "moving out of a state is published to it")
+	assert.Empty(t, byUID.Changes())
+
+	colorado.SetFilter(ChangeFilter{UIDs: []string{moved.UID}})
+	require.NoError(t, store.Delete(moved.UID))
+	assert.Equal(t, ChangeDeleted, receive(t, colorado).Type)
+
+	require.NoError(t, store.Replace(nil))
+	assert.Equal(t, ChangeRegenerated, receive(t, colorado).Type)
+	assert.Equal(t, ChangeRegenerated, receive(t, byUID).Type)
+}
+
+func TestChangeFeedDropsForSlowSubscribers(t *testing.T) {
+	t.Parallel()
+	feed := NewChangeFeed()
+	slow := feed.Subscribe(ChangeFilter{}, 2)
+	for i := 0; i < 5; i++ {
+		feed.Publish(Change{Type: ChangeCreated, Version: uint64(i)})
+	}
+
+	select {
+	case <-slow.Dropped():
+	default:
+		t.Fatal("drop was not signaled")
+	}
+	assert.Equal(t, uint64(3), slow.TakeDropped())
+	assert.Zero(t, slow.TakeDropped())
+	assert.Equal(t, uint64(0), receive(t, slow).Version)
+	assert.Equal(t, uint64(1), receive(t, slow).Version)
+
+	slow.Close()
+	feed.Publish(Change{Type: ChangeCreated})
+	assert.Empty(t, slow.Changes(), "closed subscriptions receive nothing")
+}
@@ -0,0 +1,64 @@
+package repository
+
+import "errors"
+
+// Kind classifies a domain error independently of the transport, so the HTTP
+// and gRPC APIs answer the same failure the same way.
+type Kind string
+
+// Kinds of domain errors.
+const (
+	// KindNotFound means the named record, job or other resource does not exist.
+	KindNotFound Kind = "not-found"
+	// KindValidation means the input was understood but breaks a rule.
+	KindValidation Kind = "validation"
+	// KindConflict means the request clashes with the current state.
+	KindConflict Kind = "conflict"
+	// KindLimitExceeded means the request asks for more than a limit allows,
+	// such as a page larger than the largest page served.
+	KindLimitExceeded Kind = "limit-exceeded"
+	// KindUnavailable means the server is at capacity and the request may
+	// succeed if retried later.
+	KindUnavailable Kind = "unavailable"
+)
+
+// FieldError describes one invalid input field.
+type FieldError struct {
+	Field   string `json:"field" example:"salary[0].annualSalary"`
+	Message string `json:"message" example:"must be gte=0"`
+}
+
+// Error is a domain error of a known Kind. Package-level Errors such as
+// ErrRecordNotFound are sentinels: match them with errors.Is and add detail
+// by wrapping them with fmt.Errorf("%w: ...").
+type Error struct {
+	Kind    Kind
+	Message string
+	// Fields lists the invalid fields of a validation error.
+	Fields []FieldError
+	// Err is the underlying error, if any.
+	Err error
+}
+
+// NewError returns an Error of kind with message.
+func NewError(kind Kind, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// KindOf returns the Kind of the first Error in err's chain, and false when
+// err is not a domain error.
+func KindOf(err error) (Kind, bool) {
+	var domainErr *Error
+	if !errors.As(err, &domainErr) {
+		return "", false
+	}
+	return domainErr.Kind, true
+}
@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"craft-fusion/craft-go/models"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryStore is an in-memory RecordStore backed by a slice. Lookups by UID go
+// through a hash index and the lastName, state and zipcode fields have
+// secondary indexes; all indexes are rebuilt on Replace and kept in sync by
+// Put and Delete. The full-text index is built on the first Search after
+// Replace and then maintained the same way. Delete leaves a tombstone in the
+// slice instead of shifting the records after it, and the slice is compacted
+// once tombstones make up half of it.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	records   []models.Record
+	positions map[string]int
+	indexes   map[Index]map[string]map[string]struct{}
+	version   uint64
+	// deleted marks the tombstoned positions of records; it is nil while
+	// there are none.
+	deleted    []bool
+	tombstones int
+
+	// searchMu serializes building the full-text index under the read lock.
+	searchMu sync.Mutex
+	search   *searchIndex
+}
+
+// NewMemoryStore returns an empty in-memory record store.
+func NewMemoryStore() *MemoryStore {
+	store := &MemoryStore{}
+	store.reindex()
+	return store
+}
+
+// Get finds a record by UID in the stored records.
+func (s *MemoryStore) Get(uid string) (models.Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	position, ok := s.positions[uid]
+	if !ok {
+		return models.Record{}, ErrRecordNotFound
+	}
+	return s.records[position], nil
+}
+
+// FindBy returns the records whose indexed field equals value, ignoring case,
+// in dataset order.
+func (s *MemoryStore) FindBy(index Index, value string) []models.Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	uids := s.indexes[index][indexKey(value)]
+	positions := make([]int, 0, len(uids))
+	for uid := range uids {
+		positions = append(positions, s.positions[uid])
+	}
+	sort.Ints(positions)
+
+	matches := make([]models.Record, len(positions))
+	for i, position := range positions {
+		matches[i] = s.records[position]
+	}
+	return matches
+}
+
+// List returns a copy of the stored records.
+func (s *MemoryStore) List() []models.Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.tombstones == 0 {
+		snapshot := make([]models.Record, len(s.records))
+		copy(snapshot, s.records)
+		return snapshot
+	}
+	snapshot := make([]models.Record, 0, len(s.records)-s.tombstones)
+	s.scan(func(record models.Record) bool {
+		snapshot = append(snapshot, record)
+		return true
+	})
+	return snapshot
+}
+
+// Scan visits the stored records in order without copying the dataset.
+func (s *MemoryStore) Scan(visit func(models.Record) bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	s.scan(visit)
+}
+
+// scan is Scan for callers holding the lock.
+func (s *MemoryStore) scan(visit func(models.Record) bool) {
+	for position, record := range s.records {
+		if s.deleted != nil && s.deleted[position] {
+			continue
+		}
+		if !visit(record) {
+			return
+		}
+	}
+}
+
+// Put inserts or replaces a record by UID.
+func (s *MemoryStore) Put(record models.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.put(record)
+	s.version++
+	return nil
+}
+
+// PutMany inserts or replaces every record by UID in one write.
+func (s *MemoryStore) PutMany(records []models.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, record := range records {
+		s.put(record)
+	}
+	s.version++
+	return nil
+}
+
+// put stores one record and updates the indexes. Callers must hold the write
+// lock.
+func (s *MemoryStore) put(record models.Record) {
+	if position, ok := s.positions[record.UID]; ok {
+		s.unindex(s.records[position])
+		if s.search != nil {
+			s.search.remove(s.records[position])
+		}
+		s.records[position] = record
+	} else {
+		s.positions[record.UID] = len(s.records)
+		s.records = append(s.records, record)
+		if s.deleted != nil {
+			s.deleted = append(s.deleted, false)
+		}
+	}
+	s.index(record)
+	if s.search != nil {
+		s.search.add(record)
+	}
+}
+
+// Delete removes a record by UID, preserving the order of the remaining records.
+func (s *MemoryStore) Delete(uid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	position, ok := s.positions[uid]
+	if !ok {
+		return ErrRecordNotFound
+	}
+	s.unindex(s.records[position])
+	if s.search != nil {
+		s.search.remove(s.records[position])
+	}
+	delete(s.positions, uid)
+	if s.deleted == nil {
+		s.deleted = make([]bool, len(s.records))
+	}
+	s.deleted[position] = true
+	s.records[position] = models.Record{}
+	s.tombstones++
+	if s.tombstones*2 >= len(s.records) {
+		s.compact()
+	}
+	s.version++
+	return nil
+}
+
+// compact drops the tombstones from s.records and moves the remaining
+// records' positions down. Callers must hold the write lock.
+func (s *MemoryStore) compact() {
+	live := s.records[:0]
+	s.scan(func(record models.Record) bool {
+		s.positions[record.UID] = len(live)
+		live = append(live, record)
+		return true
+	})
+	clear(s.records[len(live):])
+	s.records = live
+	s.deleted = nil
+	s.tombstones = 0
+}
+
+// Count returns the number of stored records.
+func (s *MemoryStore) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.records) - s.tombstones
+}
+
+// Replace swaps the stored dataset and rebuilds every index. The store takes
+// ownership of the slice; records sharing a UID collapse into the last one.
+func (s *MemoryStore) Replace(records []models.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records = records
+	s.reindex()
+	s.search = nil
+	s.version++
+	return nil
+}
+
+// Version returns the mutation counter of the dataset.
+func (s *MemoryStore) Version() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.version
+}
+
+// Search ranks the records matching every term of query, where a query term
+// matches any word it is a prefix of. It returns at most limit hits, best
+// first, and the total number of matches.
+func (s *MemoryStore) Search(query string, limit int) ([]SearchHit, int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	terms := queryTerms(query)
+	if len(terms) == 0 {
+		return []SearchHit{}, 0
+	}
+	s.searchMu.Lock()
+	if s.search == nil {
+		s.search = newSearchIndex(s.scan)
+	}
+	s.searchMu.Unlock()
+
+	positions := make([]int, 0)
+	for uid := range s.search.candidates(terms) {
+		positions = append(positions, s.positions[uid])
+	}
+	hits := make([]SearchHit, len(positions))
+	sort.Ints(positions)
+	for i, position := range positions {
+		record := s.records[position]
+		score, highlights := scoreRecord(record, terms)
+		hits[i] = SearchHit{Record: record, Score: score, Highlights: highlights}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, len(positions)
+}
+
+// reindex rebuilds all indexes from s.records. Callers must hold the write lock.
+func (s *MemoryStore) reindex() {
+	s.deleted = nil
+	s.tombstones = 0
+	s.positions = make(map[string]int, len(s.records))
+	s.indexes = make(map[Index]map[string]map[string]struct{}, len(indexedFields))
+	for index := range indexedFields {
+		s.indexes[index] = make(map[string]map[string]struct{})
+	}
+
+	unique := s.records[:0]
+	for _, record := range s.records {
+		if position, ok := s.positions[record.UID]; ok {
+			s.unindex(unique[position])
+			unique[position] = record
+		} else {
+			s.positions[record.UID] = len(unique)
+			unique = append(unique, record)
+		}
+		s.index(record)
+	}
+	s.records = unique
+}
+
+func (s *MemoryStore) index(record models.Record) {
+	for index, field := range indexedFields {
+		key := indexKey(field(record))
+		uids, ok := s.indexes[index][key]
+		if !ok {
+			uids = make(map[string]struct{})
+			s.indexes[index][key] = uids
+		}
+		uids[record.UID] = struct{}{}
+	}
+}
+
+func (s *MemoryStore) unindex(record models.Record) {
+	for index, field := range indexedFields {
+		key := indexKey(field(record))
+		uids := s.indexes[index][key]
+		delete(uids, record.UID)
+		if len(uids) == 0 {
+			delete(s.indexes[index], key)
+		}
+	}
+}
+
+func indexKey(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
@@ -1,75 +1,106 @@
 package repository
 
 import (
+	"context"
+	"craft-fusion/craft-go/generator"
 	"craft-fusion/craft-go/models"
-	"errors"
-	"strconv"
+	"math/rand/v2"
+	"runtime"
 	"sync"
+	"sync/atomic"
 
 	"github.com/brianvoe/gofakeit/v6"
 )
 
-var (
-	records     []models.Record
-	recordsLock sync.RWMutex
-)
+// maxRandomSeed keeps generated seeds within JavaScript's safe integer range
+// so clients can echo them back without losing precision.
+const maxRandomSeed = 1 << 53
 
-// GenerateMockRecords generates a slice of mock records and stores them in memory.
-func GenerateMockRecords(limit int) []models.Record {
-	recordsLock.Lock()
-	defer recordsLock.Unlock()
+// ResolveSeed returns seed unchanged, or a random non-zero seed when seed is 0.
+// gofakeit treats 0 as "seed from crypto/rand", so a dataset is only
+// reproducible when generated from the non-zero seed this returns.
+func ResolveSeed(seed int64) int64 {
+	if seed != 0 {
+		return seed
+	}
+	return rand.Int64N(maxRandomSeed-1) + 1
+}
 
-	records = make([]models.Record, limit)
-	gofakeit.Seed(0)
+// generationChunkSize is the number of records generated from one derived
+// seed. Chunks are the unit of parallel work, and because their seeds depend
+// only on the dataset seed and chunk index, the output does not depend on how
+// many workers generated it.
+const generationChunkSize = 4096
 
-	for i := 0; i < limit; i++ {
-		extension := gofakeit.Number(1000, 9999)
-		extensionStr := strconv.Itoa(extension)
-		records[i] = models.Record{
-			UID:       gofakeit.UUID(),
-			FirstName: gofakeit.FirstName(),
-			LastName:  gofakeit.LastName(),
-			Address: models.Address{
-				Street:  gofakeit.StreetName(),
-				City:    gofakeit.City(),
-				State:   gofakeit.State(),
-				Zipcode: gofakeit.Zip(),
-			},
-			Phone: models.Phone{
-				Extension: &extensionStr,
-				HasExtension: func() *bool {
-					hasExt := gofakeit.Bool()
-					return &hasExt
-				}(),
-			},
-			Salary: []models.Company{
-				{
-					UID:          strconv.Itoa(gofakeit.Number(100000, 999999)),
-					EmployeeName: gofakeit.Name(),
-					AnnualSalary: gofakeit.Price(50000, 200000),
-					CompanyName:  gofakeit.Company(),
-					CompanyPosition: func() *string {
-						position := gofakeit.JobTitle()
-						return &position
-					}(),
-				},
-			},
-			TotalHouseholdIncome: gofakeit.Price(100000, 500000),
-		}
-	}
+// GenerateMockRecords generates records from the default built-in profile.
+func GenerateMockRecords(limit int, seed int64) []models.Record {
+	return GenerateRecords(generator.Builtin().Default(), limit, seed)
+}
 
+// GenerateRecords generates a slice of mock records from profile and seed, so
+// equal seeds yield identical datasets. Generation is sharded across
+// GOMAXPROCS workers, each with its own faker, and touches no RecordStore:
+// callers decide where, if anywhere, the dataset is written.
+func GenerateRecords(profile *generator.Profile, limit int, seed int64) []models.Record {
+	records, _ := GenerateRecordsContext(context.Background(), profile, limit, seed, nil)
 	return records
 }
 
-// FindRecordByUID finds a record by UID in the stored records.
-func FindRecordByUID(uid string) (models.Record, error) {
-	recordsLock.RLock()
-	defer recordsLock.RUnlock()
+// GenerateRecordsContext is GenerateRecords with cancellation and progress.
+// Workers stop at the next chunk boundary once ctx is done and the context
+// error is returned. progress, when not nil, is called from the workers
+// after every chunk with the number of records generated so far.
+func GenerateRecordsContext(ctx context.Context, profile *generator.Profile, limit int, seed int64, progress func(generated int)) ([]models.Record, error) {
+	seed = ResolveSeed(seed)
+	records := make([]models.Record, limit)
+	chunks := (limit + generationChunkSize - 1) / generationChunkSize
+	workers := min(runtime.GOMAXPROCS(0), chunks)
 
-	for _, record := range records {
-		if record.UID == uid {
-			return record, nil
-		}
+	var next, generated atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				chunk := int(next.Add(1) - 1)
+				if chunk >= chunks {
+					return
+				}
+				start := chunk * generationChunkSize
+				end := min(start+generationChunkSize, limit)
+				faker := gofakeit.NewUnlocked(chunkSeed(seed, chunk))
+				for i := start; i < end; i++ {
+					records[i] = profile.Record(faker)
+				}
+				done := generated.Add(int64(end - start))
+				if progress != nil {
+					progress(int(done))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// chunkSeed derives the seed for one generation chunk. The first chunk uses
+// the dataset seed directly; later chunks mix in their index with SplitMix64.
+func chunkSeed(seed int64, chunk int) int64 {
+	if chunk == 0 {
+		return seed
+	}
+	z := uint64(seed) + uint64(chunk)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	z ^= z >> 31
+	if z == 0 {
+		// gofakeit treats 0 as "seed from crypto/rand".
+		return 1
 	}
-	return models.Record{}, errors.New("record not found")
+	return int64(z)
 }
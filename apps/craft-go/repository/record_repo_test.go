@@ -1,6 +1,14 @@
 package repository
 
 import (
+	"context"
+	"craft-fusion/craft-go/generator"
+	"craft-fusion/craft-go/models"
+	"fmt"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -8,7 +16,7 @@ import (
 )
 
 func TestGenerateMockRecordsReturnsRequestedMaterialTableDataset(t *testing.T) {
-	generated := GenerateMockRecords(3)
+	generated := GenerateMockRecords(3, 0)
 
 	require.Len(t, generated, 3)
 	for _, record := range generated {
@@ -18,27 +26,284 @@ func TestGenerateMockRecordsReturnsRequestedMaterialTableDataset(t *testing.T) {
 		assert.NotEmpty(t, record.Address.City)
 		assert.NotEmpty(t, record.Address.State)
 		assert.NotEmpty(t, record.Address.Zipcode)
-		require.Len(t, record.Salary, 1)
+		assert.NotEmpty(t, record.Email)
+		assert.NotEmpty(t, record.BirthDate)
+		assert.NotEmpty(t, record.RegistrationDate)
+		assert.NotEmpty(t, record.Phone.Number)
+		require.NotEmpty(t, record.Salary)
 		assert.Greater(t, record.Salary[0].AnnualSalary, float64(0))
 	}
 }
 
-func TestGenerateMockRecordsReplacesStoredDataset(t *testing.T) {
-	GenerateMockRecords(3)
-	replacement := GenerateMockRecords(1)
+func TestGenerateMockRecordsIsDeterministicForSeed(t *testing.T) {
+	first := GenerateMockRecords(50, 7)
+	second := GenerateMockRecords(50, 7)
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first[0].UID, GenerateMockRecords(1, 8)[0].UID)
+}
+
+func TestGenerateMockRecordsIsIndependentOfWorkerCount(t *testing.T) {
+	size := 3*generationChunkSize + 17
+	previous := runtime.GOMAXPROCS(1)
+	serial := GenerateMockRecords(size, 11)
+	runtime.GOMAXPROCS(4)
+	parallel := GenerateMockRecords(size, 11)
+	runtime.GOMAXPROCS(previous)
+
+	require.Len(t, parallel, size)
+	assert.Equal(t, serial, parallel)
+
+	uids := make(map[string]struct{}, size)
+	for _, record := range parallel {
+		uids[record.UID] = struct{}{}
+	}
+	assert.Len(t, uids, size, "chunks must not repeat each other's records")
+}
+
+func TestResolveSeed(t *testing.T) {
+	assert.Equal(t, int64(7), ResolveSeed(7))
+	random := ResolveSeed(0)
+	assert.NotZero(t, random)
+	assert.Less(t, random, int64(maxRandomSeed))
+}
+
+func TestMemoryStoreReplaceSwapsStoredDataset(t *testing.T) {
+	store := NewMemoryStore()
+	require.NoError(t, store.Replace(GenerateMockRecords(3, 0)))
+	replacement := GenerateMockRecords(1, 0)
+	require.NoError(t, store.Replace(replacement))
 
-	require.Len(t, replacement, 1)
-	_, err := FindRecordByUID(replacement[0].UID)
+	assert.Equal(t, 1, store.Count())
+	_, err := store.Get(replacement[0].UID)
 	require.NoError(t, err)
 }
 
-func TestFindRecordByUID(t *testing.T) {
-	generated := GenerateMockRecords(2)
+func TestMemoryStoreGet(t *testing.T) {
+	store := NewMemoryStore()
+	generated := GenerateMockRecords(2, 0)
+	require.NoError(t, store.Replace(generated))
 
-	found, err := FindRecordByUID(generated[0].UID)
+	found, err := store.Get(generated[0].UID)
 	require.NoError(t, err)
 	assert.Equal(t, generated[0], found)
 
-	_, err = FindRecordByUID("missing-record")
+	_, err = store.Get("missing-record")
+	assert.ErrorIs(t, err, ErrRecordNotFound)
 	assert.EqualError(t, err, "record not found")
 }
+
+func TestMemoryStorePutAndDelete(t *testing.T) {
+	store := NewMemoryStore()
+	generated := GenerateMockRecords(3, 0)
+	require.NoError(t, store.Replace(append([]models.Record(nil), generated...)))
+
+	updated := generated[1]
+	updated.FirstName = "Updated"
+	require.NoError(t, store.Put(updated))
+	assert.Equal(t, 3, store.Count())
+	found, err := store.Get(updated.UID)
+	require.NoError(t, err)
+	assert.Equal(t, "Updated", found.FirstName)
+
+	extra := GenerateMockRecords(1, 0)[0]
+	extra.UID = "extra-record"
+	require.NoError(t, store.Put(extra))
+	assert.Equal(t, 4, store.Count())
+
+	version := store.Version()
+	require.NoError(t, store.Delete(generated[0].UID))
+	assert.Greater(t, store.Version(), version)
+	version = store.Version()
+	assert.ErrorIs(t, store.Delete(generated[0].UID), ErrRecordNotFound)
+	assert.Equal(t, version, store.Version())
+	listed := store.List()
+	require.Len(t, listed, 3)
+	assert.Equal(t, []string{generated[1].UID, generated[2].UID, "extra-record"},
+		[]string{listed[0].UID, listed[1].UID, listed[2].UID})
+}
+
+func TestMemoryStoreDeleteLeavesTombstonesUntilCompaction(t *testing.T) {
+	store := NewMemoryStore()
+	generated := GenerateMockRecords(10, 3)
+	require.NoError(t, store.Replace(append([]models.Record(nil), generated...)))
+
+	want := append([]models.Record(nil), generated...)
+	for _, i := range []int{1, 4, 7, 2} {
+		require.NoError(t, store.Delete(generated[i].UID))
+		want = slices.DeleteFunc(want, func(record models.Record) bool { return record.UID == generated[i].UID })
+
+		assert.Equal(t, want, store.List())
+		assert.Equal(t, len(want), store.Count())
+		var scanned []models.Record
+		store.Scan(func(record models.Record) bool {
+			scanned = append(scanned, record)
+			return true
+		})
+		assert.Equal(t, want, scanned)
+		for _, record := range want {
+			found, err := store.Get(record.UID)
+			require.NoError(t, err)
+			assert.Equal(t, record, found)
+			assert.Contains(t, store.FindBy(IndexLastName, record.LastName), record)
+		}
+		_, err := store.Get(generated[i].UID)
+		assert.ErrorIs(t, err, ErrRecordNotFound)
+	}
+
+	added := GenerateMockRecords(1, 11)[0]
+	require.NoError(t, store.Put(added))
+	want = append(want, added)
+	assert.Equal(t, want, store.List(), "records put after deletes come last")
+	hits, _ := store.Search(added.LastName, 0)
+	assert.True(t, slices.ContainsFunc(hits, func(hit SearchHit) bool { return hit.Record.UID == added.UID }))
+	for _, hit := range hits {
+		assert.NotEmpty(t, hit.Record.UID, "tombstones never match a search")
+	}
+
+	for _, i := range []int{0, 3} {
+		require.NoError(t, store.Delete(generated[i].UID))
+	}
+	want = slices.DeleteFunc(want, func(record models.Record) bool {
+		return record.UID == generated[0].UID || record.UID == generated[3].UID
+	})
+	assert.Equal(t, want, store.List(), "compaction keeps the order")
+	hits, _ = store.Search(added.LastName, 0)
+	assert.True(t, slices.ContainsFunc(hits, func(hit SearchHit) bool { return hit.Record.UID == added.UID }))
+	for _, hit := range hits {
+		assert.NotEmpty(t, hit.Record.UID, "tombstones never match a search")
+	}
+}
+
+func TestMemoryStorePutManyMergesByUID(t *testing.T) {
+	store := NewMemoryStore()
+	generated := GenerateMockRecords(2, 0)
+	require.NoError(t, store.Replace(append([]models.Record(nil), generated...)))
+
+	updated := generated[0]
+	updated.LastName = "Merged"
+	added := GenerateMockRecords(1, 3)[0]
+	version := store.Version()
+	require.NoError(t, store.PutMany([]models.Record{updated, added}))
+
+	assert.Equal(t, version+1, store.Version())
+	assert.Equal(t, []models.Record{updated, generated[1], added}, store.List())
+	assert.Equal(t, []models.Record{updated}, store.FindBy(IndexLastName, "merged"))
+}
+
+func TestMemoryStoreSecondaryIndexesFollowMutations(t *testing.T) {
+	store := NewMemoryStore()
+	generated := GenerateMockRecords(3, 0)
+	generated[0].LastName, generated[0].Address.State = "Smith", "Colorado"
+	generated[1].LastName, generated[1].Address.State = "smith", "Vermont"
+	generated[2].LastName, generated[2].Address.State = "Jones", "Colorado"
+	require.NoError(t, store.Replace(append([]models.Record(nil), generated...)))
+
+	assert.Equal(t, []models.Record{generated[0], generated[1]}, store.FindBy(IndexLastName, "SMITH"))
+	assert.Equal(t, []models.Record{generated[0], generated[2]}, store.FindBy(IndexState, "colorado"))
+	assert.Equal(t, []models.Record{generated[2]}, store.FindBy(IndexZipcode, generated[2].Address.Zipcode))
+
+	moved := generated[0]
+	moved.Address.State = "Vermont"
+	require.NoError(t, store.Put(moved))
+	assert.Equal(t, []models.Record{generated[2]}, store.FindBy(IndexState, "Colorado"))
+	assert.Equal(t, []models.Record{moved, generated[1]}, store.FindBy(IndexState, "Vermont"))
+
+	require.NoError(t, store.Delete(moved.UID))
+	assert.Equal(t, []models.Record{generated[1]}, store.FindBy(IndexLastName, "Smith"))
+	assert.Empty(t, store.FindBy(IndexZipcode, "00000"))
+	found, err := store.Get(generated[2].UID)
+	require.NoError(t, err)
+	assert.Equal(t, generated[2], found)
+}
+
+func TestMemoryStoreReplaceCollapsesDuplicateUIDs(t *testing.T) {
+	store := NewMemoryStore()
+	generated := GenerateMockRecords(2, 0)
+	duplicate := generated[0]
+	duplicate.LastName = "Duplicate"
+	require.NoError(t, store.Replace([]models.Record{generated[0], generated[1], duplicate}))
+
+	assert.Equal(t, 2, store.Count())
+	assert.Equal(t, []models.Record{duplicate}, store.FindBy(IndexLastName, "Duplicate"))
+	assert.Empty(t, store.FindBy(IndexLastName, generated[0].LastName))
+}
+
+// BenchmarkGenerateMockRecords reports generation throughput. Run it with
+// -cpu (for example -cpu 1,2,4,8) to compare throughput across GOMAXPROCS.
+func BenchmarkGenerateMockRecords(b *testing.B) {
+	for _, size := range []int{10000, 100000} {
+		b.Run(fmt.Sprintf("records=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				GenerateMockRecords(size, 1)
+			}
+			b.ReportMetric(float64(size*b.N)/b.Elapsed().Seconds(), "records/s")
+		})
+	}
+}
+
+// indexedDataset builds size cheap records so lookup benchmarks can reach the
+// 1,000,000 record cap without paying for faker generation.
+func indexedDataset(size int) []models.Record {
+	records := make([]models.Record, size)
+	for i := range records {
+		records[i] = models.Record{
+			UID:      "uid-" + strconv.Itoa(i),
+			LastName: "last-" + strconv.Itoa(i%1000),
+			Address:  models.Address{State: "state-" + strconv.Itoa(i%50), Zipcode: strconv.Itoa(10000 + i%90000)},
+		}
+	}
+	return records
+}
+
+// BenchmarkMemoryStoreGet shows that UID lookups stay flat as the dataset grows.
+func BenchmarkMemoryStoreGet(b *testing.B) {
+	for _, size := range []int{1000, 10000, 100000, 1000000} {
+		store := NewMemoryStore()
+		require.NoError(b, store.Replace(indexedDataset(size)))
+		uid := "uid-" + strconv.Itoa(size-1)
+
+		b.Run(fmt.Sprintf("records=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := store.Get(uid); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkMemoryStoreFindByLastName measures secondary index lookups, whose
+// cost tracks the number of matches rather than the dataset size.
+func BenchmarkMemoryStoreFindByLastName(b *testing.B) {
+	for _, size := range []int{1000, 10000, 100000} {
+		store := NewMemoryStore()
+		require.NoError(b, store.Replace(indexedDataset(size)))
+
+		b.Run(fmt.Sprintf("records=%d", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				store.FindBy(IndexLastName, "last-7")
+			}
+		})
+	}
+}
+
+func TestGenerateRecordsContextReportsProgressAndCancels(t *testing.T) {
+	size := 2*generationChunkSize + 5
+	var mu sync.Mutex
+	var reported []int
+	records, err := GenerateRecordsContext(context.Background(), generator.Builtin().Default(), size, 11, func(generated int) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, generated)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, GenerateMockRecords(size, 11), records)
+	assert.Len(t, reported, 3)
+	assert.Contains(t, reported, size)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	records, err = GenerateRecordsContext(ctx, generator.Builtin().Default(), size, 11, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, records)
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"craft-fusion/craft-go/config"
+	"craft-fusion/craft-go/models"
+	"fmt"
+)
+
+// ErrRecordNotFound is returned when no record matches the requested UID.
+var ErrRecordNotFound = NewError(KindNotFound, "record not found")
+
+// Index names a record field with a secondary index.
+type Index string
+
+// Secondary indexes maintained by every RecordStore.
+const (
+	IndexLastName Index = "lastName"
+	IndexState    Index = "state"
+	IndexZipcode  Index = "zipcode"
+)
+
+// indexedFields extracts the value each secondary index is keyed on.
+var indexedFields = map[Index]func(models.Record) string{
+	IndexLastName: func(record models.Record) string { return record.LastName },
+	IndexState:    func(record models.Record) string { return record.Address.State },
+	IndexZipcode:  func(record models.Record) string { return record.Address.Zipcode },
+}
+
+// RecordStore is the storage contract for user records. Implementations must
+// be safe for concurrent use.
+type RecordStore interface {
+	// Get returns the record with the given UID or ErrRecordNotFound.
+	Get(uid string) (models.Record, error)
+	// FindBy returns the records whose indexed field matches value, ignoring case.
+	FindBy(index Index, value string) []models.Record
+	// List returns a snapshot of every stored record.
+	List() []models.Record
+	// Scan calls visit for each stored record in dataset order until visit
+	// returns false. visit runs under the store's read lock and must not call
+	// back into the store's mutating methods.
+	Scan(visit func(models.Record) bool)
+	// Put inserts the record, replacing any existing record with the same UID.
+	Put(record models.Record) error
+	// PutMany stores every record like Put, all or nothing.
+	PutMany(records []models.Record) error
+	// Delete removes the record with the given UID or returns ErrRecordNotFound.
+	Delete(uid string) error
+	// Count returns the number of stored records.
+	Count() int
+	// Version increases every time the dataset changes, so derived results
+	// can be cached until the next mutation.
+	Version() uint64
+	// Search returns up to limit records matching every term of a full-text
+	// query, best match first, and the total number of matches.
+	Search(query string, limit int) ([]SearchHit, int)
+	// Replace swaps the entire dataset for the given records.
+	Replace(records []models.Record) error
+}
+
+// OpenStore returns the RecordStore selected by the configuration. The returned
+// close function releases any resources held by the store.
+func OpenStore(cfg config.Config) (RecordStore, func() error, error) {
+	switch cfg.RecordStore {
+	case config.StoreMemory:
+		return NewMemoryStore(), func() error { return nil }, nil
+	case config.StoreBolt:
+		store, err := NewBoltStore(cfg.DataDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, store.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown record store %q", cfg.RecordStore)
+	}
+}
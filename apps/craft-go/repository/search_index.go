@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"craft-fusion/craft-go/models"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SearchHit is one full-text search result.
+type SearchHit struct {
+	Record     models.Record `json:"record"`
+	Score      float64       `json:"score" example:"7.5"`
+	Highlights []Highlight   `json:"highlights"`
+}
+
+// Highlight marks a matched token as the byte range [Start, End) of a field
+// value. Field is the JSON path of the value, for example address.city or
+// salary[0].companyName.
+type Highlight struct {
+	Field string `json:"field" example:"address.city"`
+	Start int    `json:"start" example:"0"`
+	End   int    `json:"end" example:"6"`
+}
+
+// searchField is a record value covered by full-text search. Weight scales
+// the score of matches in the field.
+type searchField struct {
+	path   string
+	weight float64
+	value  string
+}
+
+// searchFields lists the searchable values of a record.
+func searchFields(record models.Record) []searchField {
+	fields := []searchField{
+		{"firstName", 3, record.FirstName},
+		{"lastName", 3, record.LastName},
+		{"email", 2, record.Email},
+		{"address.street", 1, record.Address.Street},
+		{"address.city", 2, record.Address.City},
+		{"address.state", 2, record.Address.State},
+		{"address.zipcode", 1, record.Address.Zipcode},
+	}
+	for i, company := range record.Salary {
+		fields = append(fields, searchField{fmt.Sprintf("salary[%d].companyName", i), 1.5, company.CompanyName})
+		if company.CompanyPosition != nil {
+			fields = append(fields, searchField{fmt.Sprintf("salary[%d].companyPosition", i), 1, *company.CompanyPosition})
+		}
+	}
+	return fields
+}
+
+// token is a case-folded term and its byte range in the source text.
+type token struct {
+	term       string
+	start, end int
+}
+
+// tokenize splits text on anything that is not a letter or digit and folds
+// the resulting terms to lower case.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		wordRune := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case wordRune && start < 0:
+			start = i
+		case !wordRune && start >= 0:
+			tokens = append(tokens, token{strings.ToLower(text[start:i]), start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{strings.ToLower(text[start:]), start, len(text)})
+	}
+	return tokens
+}
+
+// searchIndex is an inverted index from terms to the UIDs of the records
+// containing them. Terms are also kept sorted so a query token can match
+// every term it is a prefix of.
+type searchIndex struct {
+	postings map[string]map[string]struct{}
+	terms    []string
+}
+
+func newSearchIndex(scan func(visit func(models.Record) bool)) *searchIndex {
+	index := &searchIndex{postings: make(map[string]map[string]struct{})}
+	scan(func(record models.Record) bool {
+		for _, term := range recordTerms(record) {
+			uids, ok := index.postings[term]
+			if !ok {
+				uids = make(map[string]struct{})
+				index.postings[term] = uids
+			}
+			uids[record.UID] = struct{}{}
+		}
+		return true
+	})
+	index.terms = make([]string, 0, len(index.postings))
+	for term := range index.postings {
+		index.terms = append(index.terms, term)
+	}
+	sort.Strings(index.terms)
+	return index
+}
+
+// recordTerms returns the distinct terms of a record's searchable fields.
+func recordTerms(record models.Record) []string {
+	seen := make(map[string]struct{})
+	var terms []string
+	for _, field := range searchFields(record) {
+		for _, token := range tokenize(field.value) {
+			if _, ok := seen[token.term]; !ok {
+				seen[token.term] = struct{}{}
+				terms = append(terms, token.term)
+			}
+		}
+	}
+	return terms
+}
+
+func (index *searchIndex) add(record models.Record) {
+	for _, term := range recordTerms(record) {
+		uids, ok := index.postings[term]
+		if !ok {
+			uids = make(map[string]struct{})
+			index.postings[term] = uids
+			position, _ := slices.BinarySearch(index.terms, term)
+			index.terms = slices.Insert(index.terms, position, term)
+		}
+		uids[record.UID] = struct{}{}
+	}
+}
+
+func (index *searchIndex) remove(record models.Record) {
+	for _, term := range recordTerms(record) {
+		uids := index.postings[term]
+		delete(uids, record.UID)
+		if len(uids) == 0 {
+			delete(index.postings, term)
+			if position, found := slices.BinarySearch(index.terms, term); found {
+				index.terms = slices.Delete(index.terms, position, position+1)
+			}
+		}
+	}
+}
+
+// candidates returns the UIDs of records holding a term that starts with
+// every query term.
+func (index *searchIndex) candidates(queryTerms []string) map[string]struct{} {
+	var result map[string]struct{}
+	for _, queryTerm := range queryTerms {
+		matches := make(map[string]struct{})
+		start, _ := slices.BinarySearch(index.terms, queryTerm)
+		for _, term := range index.terms[start:] {
+			if !strings.HasPrefix(term, queryTerm) {
+				break
+			}
+			for uid := range index.postings[term] {
+				if result == nil {
+					matches[uid] = struct{}{}
+				} else if _, ok := result[uid]; ok {
+					matches[uid] = struct{}{}
+				}
+			}
+		}
+		result = matches
+		if len(result) == 0 {
+			break
+		}
+	}
+	return result
+}
+
+// scoreRecord ranks a candidate record. Each query term adds the weight of
+// every field token it matches, doubled for whole-term matches over prefix
+// matches.
+func scoreRecord(record models.Record, queryTerms []string) (float64, []Highlight) {
+	var score float64
+	highlights := []Highlight{}
+	for _, field := range searchFields(record) {
+		for _, token := range tokenize(field.value) {
+			matched := false
+			for _, queryTerm := range queryTerms {
+				switch {
+				case token.term == queryTerm:
+					score += 2 * field.weight
+					matched = true
+				case strings.HasPrefix(token.term, queryTerm):
+					score += field.weight
+					matched = true
+				}
+			}
+			if matched {
+				highlights = append(highlights, Highlight{Field: field.path, Start: token.start, End: token.end})
+			}
+		}
+	}
+	return score, highlights
+}
+
+// queryTerms tokenizes a search query, dropping repeated terms.
+func queryTerms(query string) []string {
+	var terms []string
+	for _, token := range tokenize(query) {
+		if !slices.Contains(terms, token.term) {
+			terms = append(terms, token.term)
+		}
+	}
+	return terms
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"testing"
+
+	"craft-fusion/craft-go/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSearchTestStore(t *testing.T) *MemoryStore {
+	t.Helper()
+	engineer := "Software Engineer"
+	store := NewMemoryStore()
+	require.NoError(t, store.Replace([]models.Record{
+		{UID: "1", FirstName: "Ann", LastName: "Smith", Email: "ann@example.com", Address: models.Address{City: "Denver", State: "Colorado"}},
+		{UID: "2", FirstName: "Smithers", LastName: "Jones", Address: models.Address{City: "Denver", State: "Colorado"}},
+		{UID: "3", FirstName: "Cy", LastName: "Smith", Address: models.Address{City: "Burlington", State: "Vermont"},
+			Salary: []models.Company{{CompanyName: "Denver Tools", CompanyPosition: &engineer}}},
+		{UID: "4", FirstName: "Di", LastName: "Adams", Address: models.Address{City: "Boulder", State: "Colorado"}},
+	}))
+	return store
+}
+
+func searchUIDs(hits []SearchHit) []string {
+	uids := make([]string, len(hits))
+	for i, hit := range hits {
+		uids[i] = hit.Record.UID
+	}
+	return uids
+}
+
+func TestTokenizeFoldsCaseAndKeepsOffsets(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, []token{{"ann", 0, 3}, {"example", 4, 11}, {"com", 12, 15}}, tokenize("Ann@example.com"))
+	assert.Equal(t, []token{{"münchen", 1, 9}}, tokenize(" München!"))
+}
+
+func TestMemoryStoreSearchRanksAndHighlights(t *testing.T) {
+	t.Parallel()
+	store := newSearchTestStore(t)
+
+	hits, total := store.Search("smith denver", 0)
+	assert.Equal(t, 3, total)
+	// Whole-word matches outrank the prefix match on Smithers.
+	assert.Equal(t, []string{"1", "3", "2"}, searchUIDs(hits))
+	assert.Equal(t, []Highlight{
+		{Field: "lastName", Start: 0, End: 5},
+		{Field: "address.city", Start: 0, End: 6},
+	}, hits[0].Highlights)
+	assert.Contains(t, hits[1].Highlights, Highlight{Field: "salary[0].companyName", Start: 0, End: 6})
+
+	hits, total = store.Search("COL", 1)
+	assert.Equal(t, 3, total)
+	assert.Len(t, hits, 1)
+
+	hits, total = store.Search("engineer", 0)
+	assert.Equal(t, []string{"3"}, searchUIDs(hits))
+	assert.Equal(t, 1, total)
+
+	hits, total = store.Search("  ,; ", 0)
+	assert.Empty(t, hits)
+	assert.Zero(t, total)
+}
+
+func TestMemoryStoreSearchFollowsMutations(t *testing.T) {
+	t.Parallel()
+	store := newSearchTestStore(t)
+	_, total := store.Search("adams", 0)
+	require.Equal(t, 1, total)
+
+	require.NoError(t, store.Put(models.Record{UID: "4", FirstName: "Di", LastName: "Zimmer"}))
+	require.NoError(t, store.Put(models.Record{UID: "5", FirstName: "Ada", LastName: "Adamson"}))
+	require.NoError(t, store.Delete("1"))
+
+	hits, _ := store.Search("adams", 0)
+	assert.Equal(t, []string{"5"}, searchUIDs(hits))
+	hits, _ = store.Search("zim", 0)
+	assert.Equal(t, []string{"4"}, searchUIDs(hits))
+	hits, _ = store.Search("ann", 0)
+	assert.Empty(t, hits)
+
+	require.NoError(t, store.Replace([]models.Record{{UID: "9", FirstName: "Ann", LastName: "Lee"}}))
+	hits, _ = store.Search("ann", 0)
+	assert.Equal(t, []string{"9"}, searchUIDs(hits))
+	assert.Empty(t, store.search.postings["adamson"])
+}
+
+func BenchmarkMemoryStoreSearch(b *testing.B) {
+	store := NewMemoryStore()
+	if err := store.Replace(GenerateMockRecords(100000, 1)); err != nil {
+		b.Fatal(err)
+	}
+	store.Search("warm", 1)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		store.Search("smith new", 20)
+	}
+}
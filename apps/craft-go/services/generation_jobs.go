@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"craft-fusion/craft-go/generator"
+	"craft-fusion/craft-go/repository"
+	"sync"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+// Generation job states reported in GenerationJob.Status.
+const (
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+	JobCanceled  = "canceled"
+)
+
+// maxRunningJobs bounds the jobs generating at once; each holds its whole
+// dataset in memory until it is stored.
+const maxRunningJobs = 2
+
+// maxFinishedJobs bounds the finished jobs kept for status requests. The
+// oldest are forgotten first.
+const maxFinishedJobs = 100
+
+// ErrJobNotFound is returned for an unknown or forgotten job ID.
+var ErrJobNotFound = repository.NewError(repository.KindNotFound, "job not found")
+
+// ErrTooManyJobs is returned when maxRunningJobs jobs are already running.
+var ErrTooManyJobs = repository.NewError(repository.KindUnavailable, "too many running jobs")
+
+// ErrJobFinished is returned when canceling a job that has already stopped.
+var ErrJobFinished = repository.NewError(repository.KindConflict, "job already finished")
+
+// GenerationJob is a snapshot of an asynchronous dataset regeneration.
+type GenerationJob struct {
+	ID     string `json:"id" example:"0b5e8f0c-2f4e-4d5c-9a51-3c1f2d7e8a90"`
+	Status string `json:"status" example:"running" enums:"running,completed,failed,canceled"`
+	Count  int    `json:"count" example:"1000000"`
+	Seed   int64  `json:"seed" example:"42"`
+	// Profile and Locale are the names requested, empty for the defaults.
+	Profile string `json:"profile,omitempty" example:"full"`
+	Locale  string `json:"locale,omitempty" example:"de-DE"`
+	// Generated counts the records generated so far, in steps of 4096.
+	Generated        int        `json:"generated" example:"409600"`
+	Percent          float64    `json:"percent" example:"40.96"`
+	RecordsPerSecond float64    `json:"recordsPerSecond" example:"250000"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	FinishedAt       *time.Time `json:"finishedAt,omitempty"`
+	Error            string     `json:"error,omitempty"`
+}
+
+// Finished reports whether the job has stopped running.
+func (j GenerationJob) Finished() bool {
+	return j.Status != JobRunning
+}
+
+// generationJob guards the snapshot of one job. Every update closes changed
+// and replaces it, waking all watchers at once.
+type generationJob struct {
+	mu       sync.Mutex
+	snapshot GenerationJob
+	changed  chan struct{}
+	cancel   context.CancelFunc
+}
+
+// update applies fn to a running job and wakes its watchers. It reports false
+// and changes nothing once the job has finished.
+func (j *generationJob) update(fn func(snapshot *GenerationJob)) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.snapshot.Finished() {
+		return false
+	}
+	fn(&j.snapshot)
+	close(j.changed)
+	j.changed = make(chan struct{})
+	return true
+}
+
+func (j *generationJob) watch() (GenerationJob, <-chan struct{}) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.snapshot, j.changed
+}
+
+// generationJobs tracks running jobs and the most recently finished ones.
+type generationJobs struct {
+	mu       sync.Mutex
+	jobs     map[string]*generationJob
+	running  int
+	finished []string
+}
+
+func (g *generationJobs) get(id string) (*generationJob, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	job, ok := g.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// done moves a job from running to finished, forgetting the oldest finished
+// jobs beyond maxFinishedJobs.
+func (g *generationJobs) done(id string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.running--
+	g.finished = append(g.finished, id)
+	for len(g.finished) > maxFinishedJobs {
+		delete(g.jobs, g.finished[0])
+		g.finished = g.finished[1:]
+	}
+}
+
+// StartGenerationJob regenerates the stored dataset in the background, like
+// RegenerateRecords, and returns the new job at once. The dataset is replaced
+// only when generation completes; a canceled or failed job leaves it as is.
+func (s *RecordService) StartGenerationJob(count int, seed int64, profileName, locale string) (GenerationJob, error) {
+	profile, err := s.profile(profileName, locale)
+	if err != nil {
+		return GenerationJob{}, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &generationJob{
+		snapshot: GenerationJob{
+			ID:        gofakeit.UUID(),
+			Status:    JobRunning,
+			Count:     count,
+			Seed:      repository.ResolveSeed(seed),
+			Profile:   profileName,
+			Locale:    locale,
+			CreatedAt: time.Now().UTC(),
+		},
+		changed: make(chan struct{}),
+		cancel:  cancel,
+	}
+
+	s.jobs.mu.Lock()
+	if s.jobs.running >= maxRunningJobs {
+		s.jobs.mu.Unlock()
+		cancel()
+		return GenerationJob{}, ErrTooManyJobs
+	}
+	if s.jobs.jobs == nil {
+		s.jobs.jobs = make(map[string]*generationJob)
+	}
+	s.jobs.jobs[job.snapshot.ID] = job
+	s.jobs.running++
+	s.jobs.mu.Unlock()
+
+	snapshot := job.snapshot
+	go s.runGenerationJob(ctx, job, profile)
+	return snapshot, nil
+}
+
+func (s *RecordService) runGenerationJob(ctx context.Context, job *generationJob, profile *generator.Profile) {
+	snapshot, _ := job.watch()
+	defer s.jobs.done(snapshot.ID)
+	defer job.cancel()
+
+	count, seed := snapshot.Count, snapshot.Seed
+	start := time.Now()
+	records, err := repository.GenerateRecordsContext(ctx, profile, count, seed, func(generated int) {
+		job.update(func(snapshot *GenerationJob) {
+			// Workers report out of order; keep the highest count.
+			if generated > snapshot.Generated {
+				snapshot.Generated = generated
+				snapshot.Percent = percentOf(generated, count)
+				snapshot.RecordsPerSecond = float64(generated) / time.Since(start).Seconds()
+			}
+		})
+	})
+	if err != nil {
+		// Only cancellation stops generation, and Cancel has already
+		// finished the job.
+		return
+	}
+
+	// Store under the job lock so a concurrent cancel either wins before the
+	// dataset is replaced or fails with ErrJobFinished.
+	job.update(func(snapshot *GenerationJob) {
+		finishedAt := time.Now().UTC()
+		snapshot.FinishedAt = &finishedAt
+		if err := s.replace(records); err != nil {
+			snapshot.Status, snapshot.Error = JobFailed, err.Error()
+			return
+		}
+		run := s.stats.Observe(SourceJob, len(records), seed, start)
+		snapshot.Status = JobCompleted
+		snapshot.Generated, snapshot.Percent = count, 100
+		snapshot.RecordsPerSecond = run.RecordsPerSecond
+	})
+}
+
+// GenerationJob returns a snapshot of the job with the given ID.
+func (s *RecordService) GenerationJob(id string) (GenerationJob, error) {
+	snapshot, _, err := s.WatchGenerationJob(id)
+	return snapshot, err
+}
+
+// WatchGenerationJob returns a snapshot of the job and a channel that is
+// closed on its next change. A finished job never changes again.
+func (s *RecordService) WatchGenerationJob(id string) (GenerationJob, <-chan struct{}, error) {
+	job, err := s.jobs.get(id)
+	if err != nil {
+		return GenerationJob{}, nil, err
+	}
+	snapshot, changed := job.watch()
+	return snapshot, changed, nil
+}
+
+// CancelGenerationJob stops a running job, leaving the stored dataset
+// untouched, and returns its final snapshot.
+func (s *RecordService) CancelGenerationJob(id string) (GenerationJob, error) {
+	job, err := s.jobs.get(id)
+	if err != nil {
+		return GenerationJob{}, err
+	}
+	canceled := job.update(func(snapshot *GenerationJob) {
+		finishedAt := time.Now().UTC()
+		snapshot.Status, snapshot.FinishedAt = JobCanceled, &finishedAt
+	})
+	job.cancel()
+	snapshot, _ := job.watch()
+	if !canceled {
+		return snapshot, ErrJobFinished
+	}
+	return snapshot, nil
+}
+
+func percentOf(generated, count int) float64 {
+	if count == 0 {
+		return 100
+	}
+	return float64(generated) * 100 / float64(count)
+}
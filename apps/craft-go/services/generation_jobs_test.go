@@ -0,0 +1,106 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"craft-fusion/craft-go/generator"
+	"craft-fusion/craft-go/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForJob follows a job until it finishes and returns every snapshot seen.
+func waitForJob(t *testing.T, service *RecordService, id string) []GenerationJob {
+	t.Helper()
+	var seen []GenerationJob
+	timeout := time.After(30 * time.Second)
+	for {
+		job, changed, err := service.WatchGenerationJob(id)
+		require.NoError(t, err)
+		seen = append(seen, job)
+		if job.Finished() {
+			return seen
+		}
+		select {
+		case <-changed:
+		case <-timeout:
+			t.Fatalf("job %s did not finish", id)
+		}
+	}
+}
+
+func TestGenerationJobReplacesDatasetWhenComplete(t *testing.T) {
+	t.Parallel()
+	service := NewRecordService(repository.NewMemoryStore())
+	job, err := service.StartGenerationJob(10000, 42, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, JobRunning, job.Status)
+	assert.Equal(t, int64(42), job.Seed)
+
+	seen := waitForJob(t, service, job.ID)
+	final := seen[len(seen)-1]
+	assert.Equal(t, JobCompleted, final.Status)
+	assert.Equal(t, 10000, final.Generated)
+	assert.Equal(t, float64(100), final.Percent)
+	assert.NotNil(t, final.FinishedAt)
+	for i := 1; i < len(seen); i++ {
+		assert.GreaterOrEqual(t, seen[i].Generated, seen[i-1].Generated, "progress never goes backwards")
+	}
+
+	assert.Equal(t, 10000, service.store.Count())
+	assert.Equal(t, repository.GenerateMockRecords(10000, 42)[9999], service.store.List()[9999])
+	runs := service.Stats().History()
+	require.Len(t, runs, 1)
+	assert.Equal(t, SourceJob, runs[0].Source)
+
+	_, err = service.CancelGenerationJob(job.ID)
+	assert.ErrorIs(t, err, ErrJobFinished)
+}
+
+func TestGenerationJobCancelKeepsDataset(t *testing.T) {
+	t.Parallel()
+	service := NewRecordService(repository.NewMemoryStore())
+	_, err := service.RegenerateRecords(3, 1, "", "")
+	require.NoError(t, err)
+
+	first, err := service.StartGenerationJob(1000000, 0, "", "")
+	require.NoError(t, err)
+	second, err := service.StartGenerationJob(1000000, 0, "", "")
+	require.NoError(t, err)
+	_, err = service.StartGenerationJob(1, 0, "", "")
+	assert.ErrorIs(t, err, ErrTooManyJobs)
+
+	for _, id := range []string{first.ID, second.ID} {
+		canceled, err := service.CancelGenerationJob(id)
+		require.NoError(t, err)
+		assert.Equal(t, JobCanceled, canceled.Status)
+		seen := waitForJob(t, service, id)
+		assert.Equal(t, JobCanceled, seen[len(seen)-1].Status)
+	}
+	assert.Equal(t, 3, service.store.Count())
+
+	require.Eventually(t, func() bool {
+		_, err := service.StartGenerationJob(1, 0, "", "")
+		return err == nil
+	}, 10*time.Second, 10*time.Millisecond, "canceled jobs free their slots")
+}
+
+func TestGenerationJobErrors(t *testing.T) {
+	t.Parallel()
+	service := NewRecordService(repository.NewMemoryStore())
+	_, err := service.GenerationJob("missing")
+	assert.ErrorIs(t, err, ErrJobNotFound)
+	_, err = service.CancelGenerationJob("missing")
+	assert.ErrorIs(t, err, ErrJobNotFound)
+	_, err = service.StartGenerationJob(1, 0, "missing", "")
+	assert.ErrorIs(t, err, generator.ErrUnknownProfile)
+	kind, _ := repository.KindOf(err)
+	assert.Equal(t, repository.KindValidation, kind)
+	_, err = service.StartGenerationJob(1, 0, "", "xx-XX")
+	assert.ErrorIs(t, err, generator.ErrUnknownLocale)
+	var domainErr *repository.Error
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, "locale", domainErr.Fields[0].Field)
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"craft-fusion/craft-go/models"
+	"sync"
+	"time"
+)
+
+// Generation sources recorded in GenerationRun.Source.
+const (
+	SourceSeed     = "seed"
+	SourceGenerate = "generate"
+	SourceJob      = "job"
+)
+
+// DefaultGenerationHistory is the number of runs kept when no size is configured.
+const DefaultGenerationHistory = 100
+
+// GenerationStats keeps a bounded, oldest-first history of generation runs.
+type GenerationStats struct {
+	mu   sync.RWMutex
+	runs []models.GenerationRun
+	// next is the ring position the next run is written to once runs is full.
+	next     int
+	capacity int
+}
+
+// NewGenerationStats returns a history that retains the last capacity runs.
+func NewGenerationStats(capacity int) *GenerationStats {
+	if capacity <= 0 {
+		capacity = DefaultGenerationHistory
+	}
+	return &GenerationStats{runs: make([]models.GenerationRun, 0, capacity), capacity: capacity}
+}
+
+// Observe records a run that generated count records from seed, starting at
+// start, and returns the stored entry.
+func (s *GenerationStats) Observe(source string, count int, seed int64, start time.Time) models.GenerationRun {
+	elapsed := time.Since(start)
+	run := models.GenerationRun{
+		Source:     source,
+		Count:      count,
+		DurationMs: float64(elapsed.Microseconds()) / 1000,
+		Seed:       seed,
+		Timestamp:  start.UTC(),
+	}
+	if elapsed > 0 {
+		run.RecordsPerSecond = float64(count) / elapsed.Seconds()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.runs) < s.capacity {
+		s.runs = append(s.runs, run)
+	} else {
+		s.runs[s.next] = run
+		s.next = (s.next + 1) % s.capacity
+	}
+	return run
+}
+
+// Latest returns the most recent run, if any.
+func (s *GenerationStats) Latest() (models.GenerationRun, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.runs) == 0 {
+		return models.GenerationRun{}, false
+	}
+	if len(s.runs) < s.capacity {
+		return s.runs[len(s.runs)-1], true
+	}
+	return s.runs[(s.next+s.capacity-1)%s.capacity], true
+}
+
+// History returns the retained runs, oldest first.
+func (s *GenerationStats) History() []models.GenerationRun {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	history := make([]models.GenerationRun, 0, len(s.runs))
+	history = append(history, s.runs[s.next:]...)
+	return append(history, s.runs[:s.next]...)
+}
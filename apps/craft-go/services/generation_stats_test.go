@@ -0,0 +1,29 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerationStatsKeepsBoundedHistory(t *testing.T) {
+	t.Parallel()
+	stats := NewGenerationStats(3)
+	_, ok := stats.Latest()
+	assert.False(t, ok)
+
+	for count := 1; count <= 5; count++ {
+		stats.Observe(SourceSeed, count, int64(count), time.Now().Add(-time.Millisecond))
+	}
+
+	history := stats.History()
+	require.Len(t, history, 3)
+	assert.Equal(t, []int{3, 4, 5}, []int{history[0].Count, history[1].Count, history[2].Count})
+	latest, ok := stats.Latest()
+	require.True(t, ok)
+	assert.Equal(t, int64(5), latest.Seed)
+	assert.GreaterOrEqual(t, latest.DurationMs, 1.0)
+	assert.InDelta(t, float64(latest.Count)/(latest.DurationMs/1000), latest.RecordsPerSecond, latest.RecordsPerSecond*0.01)
+}
@@ -0,0 +1,345 @@
+package services
+
+import (
+	"cmp"
+	"craft-fusion/craft-go/models"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultHistogramBucket is the salary histogram bucket width used when the
+// query does not set one.
+const DefaultHistogramBucket = 10000
+
+// maxCachedAggregates bounds the aggregate cache between dataset changes.
+const maxCachedAggregates = 64
+
+// AggregateQuery groups the stored dataset and computes metrics per group.
+// Grouping by a company field (companyName, companyPosition) aggregates one
+// row per employer entry instead of one row per record.
+type AggregateQuery struct {
+	GroupBy []string
+	// Metrics are count, sum/avg/min/max + Income or Salary (for example
+	// avgIncome, maxSalary), pNN + Income or Salary for percentiles (p90Income)
+	// and salaryHistogram.
+	Metrics []string
+	// Limit keeps only the largest groups by count when positive.
+	Limit int
+	// BucketSize is the salary histogram bucket width.
+	BucketSize float64
+}
+
+// HistogramBucket counts salaries in [From, From+BucketSize).
+type HistogramBucket struct {
+	From  float64 `json:"from" example:"50000"`
+	Count int     `json:"count" example:"12"`
+}
+
+// AggregateGroup holds the metrics of one group.
+type AggregateGroup struct {
+	Key       map[string]string  `json:"key"`
+	Count     int                `json:"count" example:"42"`
+	Metrics   map[string]float64 `json:"metrics,omitempty"`
+	Histogram []HistogramBucket  `json:"histogram,omitempty"`
+}
+
+// AggregateResult is the outcome of an aggregate query.
+type AggregateResult struct {
+	GroupBy     []string         `json:"groupBy"`
+	Metrics     []string         `json:"metrics"`
+	TotalGroups int              `json:"totalGroups" example:"50"`
+	Groups      []AggregateGroup `json:"groups"`
+	// DatasetVersion is the dataset version the computation started from. A
+	// write that landed during it may already be counted.
+	DatasetVersion uint64 `json:"datasetVersion" example:"3"`
+}
+
+// aggregateRow is one unit of aggregation: a record, or a record paired with
+// one of its companies when grouping by company fields.
+type aggregateRow struct {
+	record  *models.Record
+	company *models.Company
+}
+
+var groupFields = map[string]func(aggregateRow) string{
+	"state":    func(row aggregateRow) string { return row.record.Address.State },
+	"city":     func(row aggregateRow) string { return row.record.Address.City },
+	"zipcode":  func(row aggregateRow) string { return row.record.Address.Zipcode },
+	"lastName": func(row aggregateRow) string { return row.record.LastName },
+	"companyName": func(row aggregateRow) string {
+		return row.company.CompanyName
+	},
+	"companyPosition": func(row aggregateRow) string {
+		if row.company.CompanyPosition == nil {
+			return ""
+		}
+		return *row.company.CompanyPosition
+	},
+}
+
+var companyGroupFields = map[string]bool{"companyName": true, "companyPosition": true}
+
+var valueMetric = regexp.MustCompile(`^(sum|avg|min|max|p(\d{1,2}))(Income|Salary)$`)
+
+// aggregateCache holds results for the dataset version they were computed from.
+type aggregateCache struct {
+	mu      sync.Mutex
+	version uint64
+	results map[string]AggregateResult
+}
+
+func (c *aggregateCache) get(key string, version uint64) (AggregateResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.version != version {
+		return AggregateResult{}, false
+	}
+	result, ok := c.results[key]
+	return result, ok
+}
+
+func (c *aggregateCache) put(key string, result AggregateResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.version != result.DatasetVersion || c.results == nil || len(c.results) >= maxCachedAggregates {
+		c.version = result.DatasetVersion
+		c.results = make(map[string]AggregateResult)
+	}
+	c.results[key] = result
+}
+
+// Aggregate groups the stored dataset and computes the requested metrics.
+// Results are cached until the dataset changes.
+func (s *RecordService) Aggregate(query AggregateQuery) (AggregateResult, error) {
+	if query.GroupBy == nil {
+		query.GroupBy = []string{}
+	}
+	if len(query.Metrics) == 0 {
+		query.Metrics = []string{"count"}
+	}
+	if query.BucketSize == 0 {
+		query.BucketSize = DefaultHistogramBucket
+	}
+	if err := validateAggregate(query); err != nil {
+		return AggregateResult{}, err
+	}
+
+	key := fmt.Sprintf("%v|%v|%d|%g", query.GroupBy, query.Metrics, query.Limit, query.BucketSize)
+	version := s.store.Version()
+	if cached, ok := s.aggregates.get(key, version); ok {
+		return cached, nil
+	}
+
+	result := AggregateResult{GroupBy: query.GroupBy, Metrics: query.Metrics, DatasetVersion: version}
+	result.Groups = aggregateGroups(s, query)
+	result.TotalGroups = len(result.Groups)
+	if query.Limit > 0 && len(result.Groups) > query.Limit {
+		result.Groups = result.Groups[:query.Limit]
+	}
+	// A write between reading the version and the scan, which takes its own
+	// read lock, may already be counted; cache only when none happened.
+	if s.store.Version() == version {
+		s.aggregates.put(key, result)
+	}
+	return result, nil
+}
+
+func validateAggregate(query AggregateQuery) error {
+	for _, field := range query.GroupBy {
+		if _, ok := groupFields[field]; !ok {
+			return fmt.Errorf("%w: cannot group by %q", ErrInvalidQuery, field)
+		}
+	}
+	for _, metric := range query.Metrics {
+		if metric == "count" || metric == "salaryHistogram" {
+			continue
+		}
+		match := valueMetric.FindStringSubmatch(metric)
+		if match == nil || match[2] == "0" || match[2] == "00" {
+			return fmt.Errorf("%w: unknown metric %q", ErrInvalidQuery, metric)
+		}
+	}
+	if query.Limit < 0 || query.BucketSize < 0 {
+		return fmt.Errorf("%w: limit and bucketSize must not be negative", ErrInvalidQuery)
+	}
+	return nil
+}
+
+// groupAccumulator gathers the values a group's metrics are computed from.
+type groupAccumulator struct {
+	key      []string
+	count    int
+	incomes  valueStats
+	salaries valueStats
+	// histogram counts salaries by bucket start when salaryHistogram is
+	// requested.
+	histogram map[float64]int
+}
+
+// valueStats keeps running statistics of a series of values, and the values
+// themselves only when keep is set because a percentile needs them.
+type valueStats struct {
+	keep     bool
+	count    int
+	sum      float64
+	min, max float64
+	values   []float64
+}
+
+func (v *valueStats) add(value float64) {
+	if v.count == 0 || value < v.min {
+		v.min = value
+	}
+	if v.count == 0 || value > v.max {
+		v.max = value
+	}
+	v.count++
+	v.sum += value
+	if v.keep {
+		v.values = append(v.values, value)
+	}
+}
+
+func aggregateGroups(s *RecordService, query AggregateQuery) []AggregateGroup {
+	byCompany := false
+	for _, field := range query.GroupBy {
+		byCompany = byCompany || companyGroupFields[field]
+	}
+
+	keepIncomes, keepSalaries, histogram := false, false, false
+	for _, metric := range query.Metrics {
+		if metric == "salaryHistogram" {
+			histogram = true
+		} else if match := valueMetric.FindStringSubmatch(metric); match != nil && match[2] != "" {
+			keepIncomes = keepIncomes || match[3] == "Income"
+			keepSalaries = keepSalaries || match[3] == "Salary"
+		}
+	}
+
+	groups := map[string]*groupAccumulator{}
+	addSalary := func(group *groupAccumulator, salary float64) {
+		group.salaries.add(salary)
+		if group.histogram != nil {
+			group.histogram[math.Floor(salary/query.BucketSize)*query.BucketSize]++
+		}
+	}
+	add := func(row aggregateRow) {
+		key := make([]string, len(query.GroupBy))
+		for i, field := range query.GroupBy {
+			key[i] = groupFields[field](row)
+		}
+		joined := strings.Join(key, "\x00")
+		group, ok := groups[joined]
+		if !ok {
+			group = &groupAccumulator{key: key, incomes: valueStats{keep: keepIncomes}, salaries: valueStats{keep: keepSalaries}}
+			if histogram {
+				group.histogram = map[float64]int{}
+			}
+			groups[joined] = group
+		}
+		group.count++
+		group.incomes.add(row.record.TotalHouseholdIncome)
+		if row.company != nil {
+			addSalary(group, row.company.AnnualSalary)
+			return
+		}
+		for _, company := range row.record.Salary {
+			addSalary(group, company.AnnualSalary)
+		}
+	}
+
+	s.store.Scan(func(record models.Record) bool {
+		if !byCompany {
+			add(aggregateRow{record: &record})
+			return true
+		}
+		for i := range record.Salary {
+			add(aggregateRow{record: &record, company: &record.Salary[i]})
+		}
+		return true
+	})
+
+	result := make([]AggregateGroup, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, group.finish(query))
+	}
+	slices.SortFunc(result, func(a, b AggregateGroup) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		for _, field := range query.GroupBy {
+			if order := strings.Compare(a.Key[field], b.Key[field]); order != 0 {
+				return order
+			}
+		}
+		return 0
+	})
+	return result
+}
+
+func (g *groupAccumulator) finish(query AggregateQuery) AggregateGroup {
+	group := AggregateGroup{Key: make(map[string]string, len(g.key)), Count: g.count}
+	for i, field := range query.GroupBy {
+		group.Key[field] = g.key[i]
+	}
+	slices.Sort(g.incomes.values)
+	slices.Sort(g.salaries.values)
+
+	for _, metric := range query.Metrics {
+		switch metric {
+		case "count":
+			continue
+		case "salaryHistogram":
+			group.Histogram = histogramBuckets(g.histogram)
+			continue
+		}
+		match := valueMetric.FindStringSubmatch(metric)
+		values := &g.incomes
+		if match[3] == "Salary" {
+			values = &g.salaries
+		}
+		if values.count == 0 {
+			continue
+		}
+		if group.Metrics == nil {
+			group.Metrics = make(map[string]float64)
+		}
+		group.Metrics[metric] = values.summarize(match[1], match[2])
+	}
+	return group
+}
+
+// summarize computes one statistic; percentiles need the values kept and
+// sorted.
+func (v *valueStats) summarize(statistic, percentile string) float64 {
+	switch statistic {
+	case "min":
+		return v.min
+	case "max":
+		return v.max
+	case "sum":
+		return v.sum
+	case "avg":
+		return v.sum / float64(v.count)
+	}
+	p, _ := strconv.Atoi(percentile)
+	// Nearest-rank percentile.
+	rank := int(math.Ceil(float64(p) / 100 * float64(len(v.values))))
+	return v.values[max(rank, 1)-1]
+}
+
+func histogramBuckets(counts map[float64]int) []HistogramBucket {
+	var buckets []HistogramBucket
+	for from, count := range counts {
+		buckets = append(buckets, HistogramBucket{From: from, Count: count})
+	}
+	slices.SortFunc(buckets, func(a, b HistogramBucket) int { return cmp.Compare(a.From, b.From) })
+	return buckets
+}
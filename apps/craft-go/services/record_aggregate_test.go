@@ -0,0 +1,160 @@
+package services
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"craft-fusion/craft-go/models"
+	"craft-fusion/craft-go/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAggregateTestService(t *testing.T) (*RecordService, repository.RecordStore) {
+	t.Helper()
+	store := repository.NewMemoryStore()
+	require.NoError(t, store.Replace([]models.Record{
+		{UID: "1", LastName: "Smith", Address: models.Address{City: "Denver", State: "Colorado"}, TotalHouseholdIncome: 120000,
+			Salary: []models.Company{{CompanyName: "Acme", AnnualSalary: 70000}, {CompanyName: "Initech", AnnualSalary: 50000}}},
+		{UID: "2", LastName: "Jones", Address: models.Address{City: "Boulder", State: "Colorado"}, TotalHouseholdIncome: 90000,
+			Salary: []models.Company{{CompanyName: "Acme", AnnualSalary: 90000}}},
+		{UID: "3", LastName: "Smith", Address: models.Address{City: "Burlington", State: "Vermont"}, TotalHouseholdIncome: 150000,
+			Salary: []models.Company{{CompanyName: "Globex", AnnualSalary: 150000}}},
+		{UID: "4", LastName: "Adams", Address: models.Address{City: "Denver", State: "Colorado"}, TotalHouseholdIncome: 60000,
+			Salary: []models.Company{{CompanyName: "Acme", AnnualSalary: 60000}}},
+	}))
+	return NewRecordService(store), store
+}
+
+func TestAggregateGroupsByStateWithIncomeMetrics(t *testing.T) {
+	t.Parallel()
+	service, _ := newAggregateTestService(t)
+
+	result, err := service.Aggregate(AggregateQuery{
+		GroupBy: []string{"state"},
+		Metrics: []string{"count", "sumIncome", "avgIncome", "minIncome", "maxIncome", "p50Income", "p90Income"},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 2, result.TotalGroups)
+	colorado := result.Groups[0]
+	assert.Equal(t, map[string]string{"state": "Colorado"}, colorado.Key)
+	assert.Equal(t, 3, colorado.Count)
+	assert.Equal(t, map[string]float64{
+		"sumIncome": 270000,
+		"avgIncome": 90000,
+		"minIncome": 60000,
+		"maxIncome": 120000,
+		"p50Income": 90000,
+		"p90Income": 120000,
+	}, colorado.Metrics)
+	assert.Equal(t, "Vermont", result.Groups[1].Key["state"])
+}
+
+func TestAggregateTopEmployersWithSalaryHistogram(t *testing.T) {
+	t.Parallel()
+	service, _ := newAggregateTestService(t)
+
+	result, err := service.Aggregate(AggregateQuery{
+		GroupBy:    []string{"companyName"},
+		Metrics:    []string{"count", "avgSalary", "salaryHistogram"},
+		Limit:      1,
+		BucketSize: 25000,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, result.TotalGroups)
+	require.Len(t, result.Groups, 1)
+	acme := result.Groups[0]
+	assert.Equal(t, "Acme", acme.Key["companyName"])
+	assert.Equal(t, 3, acme.Count)
+	assert.InDelta(t, 73333.33, acme.Metrics["avgSalary"], 0.01)
+	assert.Equal(t, []HistogramBucket{{From: 50000, Count: 2}, {From: 75000, Count: 1}}, acme.Histogram)
+}
+
+func TestAggregateWithoutGroupingCoversDataset(t *testing.T) {
+	t.Parallel()
+	service, _ := newAggregateTestService(t)
+
+	result, err := service.Aggregate(AggregateQuery{Metrics: []string{"count", "sumSalary"}})
+	require.NoError(t, err)
+
+	require.Len(t, result.Groups, 1)
+	assert.Equal(t, 4, result.Groups[0].Count)
+	assert.Equal(t, 420000.0, result.Groups[0].Metrics["sumSalary"])
+}
+
+func TestAggregateRejectsUnknownFieldsAndMetrics(t *testing.T) {
+	t.Parallel()
+	service, _ := newAggregateTestService(t)
+
+	_, err := service.Aggregate(AggregateQuery{GroupBy: []string{"email"}})
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+	_, err = service.Aggregate(AggregateQuery{Metrics: []string{"medianIncome"}})
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+	_, err = service.Aggregate(AggregateQuery{Metrics: []string{"p0Income"}})
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
+
+func TestValueStatsKeepsValuesOnlyForPercentiles(t *testing.T) {
+	t.Parallel()
+	running := valueStats{}
+	kept := valueStats{keep: true}
+	for _, value := range []float64{30, 10, 20, 40} {
+		running.add(value)
+		kept.add(value)
+	}
+	assert.Nil(t, running.values, "sum, avg, min and max need no values")
+	assert.Equal(t, 10.0, running.summarize("min", ""))
+	assert.Equal(t, 40.0, running.summarize("max", ""))
+	assert.Equal(t, 100.0, running.summarize("sum", ""))
+	assert.Equal(t, 25.0, running.summarize("avg", ""))
+
+	slices.Sort(kept.values)
+	assert.Equal(t, 20.0, kept.summarize("p50", "50"))
+	assert.Equal(t, 40.0, kept.summarize("p90", "90"))
+}
+
+func TestAggregateCacheInvalidatedByWrites(t *testing.T) {
+	t.Parallel()
+	service, store := newAggregateTestService(t)
+	query := AggregateQuery{GroupBy: []string{"state"}}
+
+	first, err := service.Aggregate(query)
+	require.NoError(t, err)
+	cached, err := service.Aggregate(query)
+	require.NoError(t, err)
+	assert.Equal(t, first, cached)
+
+	require.NoError(t, store.Delete("3"))
+	updated, err := service.Aggregate(query)
+	require.NoError(t, err)
+	assert.Greater(t, updated.DatasetVersion, first.DatasetVersion)
+	assert.Equal(t, 1, updated.TotalGroups)
+}
+
+// racingStore deletes a record just before the first scan, as a concurrent
+// write landing between an aggregate reading the version and scanning would.
+type racingStore struct {
+	repository.RecordStore
+	once sync.Once
+	t    *testing.T
+}
+
+func (s *racingStore) Scan(visit func(models.Record) bool) {
+	s.once.Do(func() { require.NoError(s.t, s.RecordStore.Delete("3")) })
+	s.RecordStore.Scan(visit)
+}
+
+func TestAggregateSkipsCacheWhenWriteOverlapsScan(t *testing.T) {
+	t.Parallel()
+	_, store := newAggregateTestService(t)
+	service := NewRecordService(&racingStore{RecordStore: store, t: t})
+
+	result, err := service.Aggregate(AggregateQuery{GroupBy: []string{"state"}})
+	require.NoError(t, err)
+	assert.Less(t, result.DatasetVersion, store.Version())
+	assert.Empty(t, service.aggregates.results)
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"craft-fusion/craft-go/models"
+	"craft-fusion/craft-go/recordio"
+	"craft-fusion/craft-go/repository"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/go-playground/validator/v10"
+)
+
+// ErrImportRejected is returned when an import has invalid lines. Nothing is
+// written; the ImportResult lists the failing lines.
+var ErrImportRejected = repository.NewError(repository.KindValidation, "import rejected")
+
+// ErrInvalidImport is returned when the import input cannot be read at all or
+// the options are invalid.
+var ErrInvalidImport = repository.NewError(repository.KindValidation, "invalid import")
+
+// ErrEmptyReplace is returned when a replace import holds no records and
+// ImportOptions.AllowEmpty is not set, since it would wipe the dataset.
+var ErrEmptyReplace = repository.NewError(repository.KindValidation, "replace import has no records")
+
+// maxImportErrors bounds the line errors kept in an ImportResult.
+const maxImportErrors = 100
+
+// ImportMode selects how imported records combine with the stored dataset.
+type ImportMode string
+
+const (
+	// ImportReplace swaps the stored dataset for the imported records.
+	ImportReplace ImportMode = "replace"
+	// ImportMerge upserts the imported records by UID and keeps the rest.
+	ImportMerge ImportMode = "merge"
+)
+
+// ImportOptions controls an import.
+type ImportOptions struct {
+	Mode ImportMode
+	// DryRun validates the input and reports what would change without writing.
+	DryRun bool
+	// AllowEmpty confirms that a replace import without records should empty
+	// the stored dataset.
+	AllowEmpty bool
+}
+
+// ImportLineError describes why one input line was rejected.
+type ImportLineError struct {
+	Line  int    `json:"line" example:"12"`
+	Error string `json:"error" example:"firstName is required"`
+}
+
+// ImportResult summarizes an import.
+type ImportResult struct {
+	Mode   ImportMode `json:"mode" example:"merge"`
+	DryRun bool       `json:"dryRun"`
+	// Read counts the records decoded, valid or not.
+	Read int `json:"read" example:"1000"`
+	// Created and Updated count the records that were (or, in a dry run,
+	// would be) added and overwritten. Replace imports count every record
+	// as created and report the dropped dataset size in Removed.
+	Created int `json:"created" example:"990"`
+	Updated int `json:"updated" example:"10"`
+	Removed int `json:"removed" example:"0"`
+	// ErrorCount counts every rejected line; Errors lists the first 100.
+	ErrorCount int               `json:"errorCount" example:"0"`
+	Errors     []ImportLineError `json:"errors"`
+}
+
+// ImportRecords reads every record from reader, validates each against the
+// record model and, when all are valid, applies them in a single write.
+// Records without a UID get a generated one; a UID repeated within the input
+// is an error. When any line fails the store is left untouched and
+// ErrImportRejected is returned along with the result. A replace import
+// without records fails with ErrEmptyReplace unless options.AllowEmpty is set.
+func (s *RecordService) ImportRecords(reader recordio.Reader, options ImportOptions) (ImportResult, error) {
+	if options.Mode == "" {
+		options.Mode = ImportMerge
+	}
+	if options.Mode != ImportMerge && options.Mode != ImportReplace {
+		return ImportResult{}, fmt.Errorf("%w: unknown mode %q", ErrInvalidImport, options.Mode)
+	}
+	result := ImportResult{Mode: options.Mode, DryRun: options.DryRun, Errors: []ImportLineError{}}
+	reject := func(line int, message string) {
+		result.ErrorCount++
+		if len(result.Errors) < maxImportErrors {
+			result.Errors = append(result.Errors, ImportLineError{Line: line, Error: message})
+		}
+	}
+
+	var records []models.Record
+	lines := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var rowErr *recordio.RowError
+		if errors.As(err, &rowErr) {
+			result.Read++
+			reject(rowErr.Line, rowErr.Err.Error())
+			continue
+		}
+		if err != nil {
+			return result, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+		}
+
+		result.Read++
+		line := reader.Line()
+		var validationErrs validator.ValidationErrors
+		if err := ValidateRecord(record); errors.As(err, &validationErrs) {
+			reject(line, ValidationMessage(validationErrs))
+			continue
+		} else if err != nil {
+			return result, err
+		}
+		if record.UID == "" {
+			record.UID = gofakeit.UUID()
+		}
+		if first, ok := lines[record.UID]; ok {
+			reject(line, fmt.Sprintf("duplicate UID %q, first seen on line %d", record.UID, first))
+			continue
+		}
+		lines[record.UID] = line
+		records = append(records, record)
+	}
+	if result.ErrorCount > 0 {
+		return result, ErrImportRejected
+	}
+	if options.Mode == ImportReplace && len(records) == 0 && !options.AllowEmpty {
+		return result, ErrEmptyReplace
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if options.Mode == ImportReplace {
+		result.Created, result.Removed = len(records), s.store.Count()
+	} else {
+		for _, record := range records {
+			if _, err := s.store.Get(record.UID); err == nil {
+				result.Updated++
+			} else {
+				result.Created++
+			}
+		}
+	}
+	if options.DryRun {
+		return result, nil
+	}
+	if options.Mode == ImportReplace {
+		return result, s.store.Replace(records)
+	}
+	return result, s.store.PutMany(records)
+}
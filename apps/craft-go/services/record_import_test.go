@@ -0,0 +1,84 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"craft-fusion/craft-go/recordio"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func importReader(t *testing.T, format, input string) recordio.Reader {
+	t.Helper()
+	reader, err := recordio.NewReader(strings.NewReader(input), format)
+	require.NoError(t, err)
+	return reader
+}
+
+func TestImportRecordsMergesByUID(t *testing.T) {
+	t.Parallel()
+	service := newQueryTestService(t)
+	input := "UID,firstName,lastName\n1,Ann,Merged\n,New,Person\n"
+
+	result, err := service.ImportRecords(importReader(t, recordio.FormatCSV, input), ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, ImportResult{Mode: ImportMerge, Read: 2, Created: 1, Updated: 1, Errors: []ImportLineError{}}, result)
+
+	record, err := service.GetRecordByUID("1")
+	require.NoError(t, err)
+	assert.Equal(t, "Merged", record.LastName)
+	assert.Equal(t, 5, service.store.Count())
+}
+
+func TestImportRecordsReplacesDataset(t *testing.T) {
+	t.Parallel()
+	service := newQueryTestService(t)
+	input := `{"UID":"a","firstName":"Ann","lastName":"Smith"}` + "\n" + `{"UID":"b","firstName":"Bob","lastName":"Jones"}`
+
+	result, err := service.ImportRecords(importReader(t, recordio.FormatNDJSON, input), ImportOptions{Mode: ImportReplace, DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 4, result.Removed)
+	assert.Equal(t, 4, service.store.Count())
+
+	_, err = service.ImportRecords(importReader(t, recordio.FormatNDJSON, input), ImportOptions{Mode: ImportReplace})
+	require.NoError(t, err)
+	page, err := service.QueryRecords(RecordQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, recordUIDs(page.Records))
+
+	_, err = service.ImportRecords(importReader(t, recordio.FormatNDJSON, ""), ImportOptions{Mode: ImportReplace})
+	assert.ErrorIs(t, err, ErrEmptyReplace)
+	assert.Equal(t, 2, service.store.Count(), "an empty replace is not applied unconfirmed")
+	result, err = service.ImportRecords(importReader(t, recordio.FormatNDJSON, ""), ImportOptions{Mode: ImportReplace, AllowEmpty: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Removed)
+	assert.Zero(t, service.store.Count())
+}
+
+func TestImportRecordsRejectsInvalidInputAtomically(t *testing.T) {
+	t.Parallel()
+	service := newQueryTestService(t)
+	version := service.store.Version()
+	input := "UID,firstName,lastName,email,totalHouseholdIncome\n" +
+		"x,Ann,Smith,ann@example.com,10\n" +
+		"y,Bob,,not-an-email,10\n" +
+		"z,Cy,Lee,,-5\n" +
+		"x,Di,Adams,,1\n"
+
+	result, err := service.ImportRecords(importReader(t, recordio.FormatCSV, input), ImportOptions{Mode: ImportReplace})
+	assert.ErrorIs(t, err, ErrImportRejected)
+	assert.Equal(t, 4, result.Read)
+	assert.Equal(t, 3, result.ErrorCount)
+	assert.Equal(t, []ImportLineError{
+		{Line: 3, Error: "lastName is required; email must be a valid email"},
+		{Line: 4, Error: "totalHouseholdIncome must be gte=0"},
+		{Line: 5, Error: `duplicate UID "x", first seen on line 2`},
+	}, result.Errors)
+	assert.Equal(t, version, service.store.Version())
+
+	_, err = service.ImportRecords(importReader(t, recordio.FormatCSV, input), ImportOptions{Mode: "append"})
+	assert.ErrorIs(t, err, ErrInvalidImport)
+}
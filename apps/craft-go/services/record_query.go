@@ -0,0 +1,314 @@
+package services
+
+import (
+	"cmp"
+	"container/heap"
+	"craft-fusion/craft-go/models"
+	"craft-fusion/craft-go/repository"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidQuery is returned when a RecordQuery names an unknown sort field
+// or carries out-of-range paging values.
+var ErrInvalidQuery = repository.NewError(repository.KindValidation, "invalid record query")
+
+// ErrPageTooLarge is returned when a RecordQuery asks for pages of more than
+// MaxPageSize records.
+var ErrPageTooLarge = repository.NewError(repository.KindLimitExceeded, "page too large")
+
+// MaxPageSize bounds the records a single page may hold.
+const MaxPageSize = 1000000
+
+// SortField orders query results by one record field.
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+// RecordQuery filters, sorts and pages the stored dataset. Zero values mean
+// "no constraint"; a PageSize of 0 returns every matching record after the
+// first Offset, capped at Limit when Limit is set.
+type RecordQuery struct {
+	Page     int
+	PageSize int
+	Limit    int
+	// Offset skips matches of an unpaged query, as a cursor does; it is
+	// ignored when PageSize is set.
+	Offset int
+	Sort   []SortField
+
+	State     string
+	City      string
+	LastName  string
+	Zipcode   string
+	MinIncome *float64
+	MaxIncome *float64
+	// Q matches case-insensitive substrings of names, address and email.
+	Q string
+}
+
+// RecordPage is one page of query results along with the total match count.
+type RecordPage struct {
+	Records  []models.Record
+	Total    int
+	Page     int
+	PageSize int
+}
+
+// sortKeys compares two records on a sortable field.
+var sortKeys = map[string]func(a, b models.Record) int{
+	"UID":                  func(a, b models.Record) int { return strings.Compare(a.UID, b.UID) },
+	"firstName":            func(a, b models.Record) int { return strings.Compare(a.FirstName, b.FirstName) },
+	"lastName":             func(a, b models.Record) int { return strings.Compare(a.LastName, b.LastName) },
+	"city":                 func(a, b models.Record) int { return strings.Compare(a.Address.City, b.Address.City) },
+	"state":                func(a, b models.Record) int { return strings.Compare(a.Address.State, b.Address.State) },
+	"zipcode":              func(a, b models.Record) int { return strings.Compare(a.Address.Zipcode, b.Address.Zipcode) },
+	"totalHouseholdIncome": func(a, b models.Record) int { return cmp.Compare(a.TotalHouseholdIncome, b.TotalHouseholdIncome) },
+}
+
+// ParseSort parses a comma-separated sort expression such as
+// "lastName,-totalHouseholdIncome", where a leading "-" sorts descending.
+func ParseSort(expression string) ([]SortField, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, nil
+	}
+	var fields []SortField
+	for _, part := range strings.Split(expression, ",") {
+		part = strings.TrimSpace(part)
+		field := SortField{Field: strings.TrimPrefix(part, "-"), Descending: strings.HasPrefix(part, "-")}
+		if _, ok := sortKeys[field.Field]; !ok {
+			return nil, fmt.Errorf("%w: cannot sort by %q", ErrInvalidQuery, field.Field)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// QueryRecords evaluates the query against the stored dataset.
+func (s *RecordService) QueryRecords(query RecordQuery) (RecordPage, error) {
+	query, start, end, err := pageWindow(query)
+	if err != nil {
+		return RecordPage{}, err
+	}
+	window := newRecordWindow(start, end, query.Sort)
+	s.visitMatches(query, window.add)
+
+	return RecordPage{Records: window.records(), Total: window.total, Page: query.Page, PageSize: query.PageSize}, nil
+}
+
+// EachRecord evaluates the query like QueryRecords but hands the records of
+// the page to visit as they are found instead of collecting them, stopping
+// at the first error visit returns. Unsorted queries hold no records; sorted
+// ones still keep their window of matches until the scan ends. The returned
+// page carries no Records.
+func (s *RecordService) EachRecord(query RecordQuery, visit func(models.Record) error) (RecordPage, error) {
+	query, start, end, err := pageWindow(query)
+	if err != nil {
+		return RecordPage{}, err
+	}
+	page := RecordPage{Page: query.Page, PageSize: query.PageSize}
+	if len(query.Sort) > 0 {
+		window := newRecordWindow(start, end, query.Sort)
+		s.visitMatches(query, window.add)
+		page.Total = window.total
+		for _, record := range window.records() {
+			if err := visit(record); err != nil {
+				return page, err
+			}
+		}
+		return page, nil
+	}
+
+	var visitErr error
+	s.visitMatches(query, func(record models.Record) bool {
+		seq := page.Total
+		page.Total++
+		if seq >= start && (end == 0 || seq < end) {
+			visitErr = visit(record)
+		}
+		return visitErr == nil
+	})
+	return page, visitErr
+}
+
+// pageWindow validates query and returns it with defaults applied, along
+// with the positions [start, end) of the matches it selects, end 0 meaning
+// no bound.
+func pageWindow(query RecordQuery) (RecordQuery, int, int, error) {
+	if query.Page < 0 || query.PageSize < 0 || query.Limit < 0 || query.Offset < 0 {
+		return query, 0, 0, fmt.Errorf("%w: page, pageSize, limit and offset must not be negative", ErrInvalidQuery)
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize > MaxPageSize {
+		return query, 0, 0, fmt.Errorf("%w: pageSize cannot exceed %d", ErrPageTooLarge, MaxPageSize)
+	}
+	if query.PageSize > 0 && query.Page > math.MaxInt/query.PageSize {
+		return query, 0, 0, fmt.Errorf("%w: page %d is out of range", ErrInvalidQuery, query.Page)
+	}
+	for _, field := range query.Sort {
+		if _, ok := sortKeys[field.Field]; !ok {
+			return query, 0, 0, fmt.Errorf("%w: cannot sort by %q", ErrInvalidQuery, field.Field)
+		}
+	}
+
+	start, end := query.Offset, 0
+	if query.Limit > 0 {
+		end = query.Offset + query.Limit
+		if end < query.Offset {
+			end = math.MaxInt
+		}
+	}
+	if query.PageSize > 0 {
+		start, end = (query.Page-1)*query.PageSize, query.Page*query.PageSize
+	}
+	return query, start, end, nil
+}
+
+// visitMatches calls visit for each record accepted by the query filters, in
+// dataset order, until visit returns false. It narrows the candidates
+// through a secondary index when an indexed field is filtered.
+func (s *RecordService) visitMatches(query RecordQuery, visit func(models.Record) bool) {
+	q := strings.ToLower(strings.TrimSpace(query.Q))
+	accept := func(record models.Record) bool {
+		switch {
+		case query.State != "" && !strings.EqualFold(record.Address.State, query.State),
+			query.City != "" && !strings.EqualFold(record.Address.City, query.City),
+			query.LastName != "" && !strings.EqualFold(record.LastName, query.LastName),
+			query.Zipcode != "" && !strings.EqualFold(record.Address.Zipcode, query.Zipcode),
+			query.MinIncome != nil && record.TotalHouseholdIncome < *query.MinIncome,
+			query.MaxIncome != nil && record.TotalHouseholdIncome > *query.MaxIncome:
+			return false
+		case q != "":
+			return containsFold(q, record.FirstName, record.LastName, record.Email,
+				record.Address.Street, record.Address.City, record.Address.State, record.Address.Zipcode)
+		}
+		return true
+	}
+
+	var candidates []models.Record
+	switch {
+	case query.Zipcode != "":
+		candidates = s.store.FindBy(repository.IndexZipcode, query.Zipcode)
+	case query.LastName != "":
+		candidates = s.store.FindBy(repository.IndexLastName, query.LastName)
+	case query.State != "":
+		candidates = s.store.FindBy(repository.IndexState, query.State)
+	default:
+		s.store.Scan(func(record models.Record) bool {
+			return !accept(record) || visit(record)
+		})
+		return
+	}
+	for _, record := range candidates {
+		if accept(record) && !visit(record) {
+			return
+		}
+	}
+}
+
+// recordWindow keeps the matches at positions [start, end) of the query
+// order, end 0 meaning no bound, while counting every match. Unsorted
+// queries keep only the window itself; sorted ones keep the best end matches
+// in a heap rather than sorting them all.
+type recordWindow struct {
+	start, end int
+	sort       []SortField
+	total      int
+	kept       []rankedRecord
+}
+
+// rankedRecord is a match with its position in dataset order, which breaks
+// sort ties so results match a stable sort.
+type rankedRecord struct {
+	record models.Record
+	seq    int
+}
+
+func newRecordWindow(start, end int, sort []SortField) *recordWindow {
+	return &recordWindow{start: start, end: end, sort: sort}
+}
+
+// add offers a match to the window. It always returns true, so every match
+// is counted.
+func (w *recordWindow) add(record models.Record) bool {
+	seq := w.total
+	w.total++
+	switch {
+	case len(w.sort) == 0:
+		if seq >= w.start && (w.end == 0 || seq < w.end) {
+			w.kept = append(w.kept, rankedRecord{record, seq})
+		}
+	case w.end > 0 && len(w.kept) == w.end:
+		// The heap is full: its root is the worst kept match.
+		if w.compare(rankedRecord{record, seq}, w.kept[0]) < 0 {
+			w.kept[0] = rankedRecord{record, seq}
+			heap.Fix(w, 0)
+		}
+	default:
+		heap.Push(w, rankedRecord{record, seq})
+	}
+	return true
+}
+
+// records returns the window in query order.
+func (w *recordWindow) records() []models.Record {
+	if len(w.sort) > 0 {
+		slices.SortFunc(w.kept, w.compare)
+		w.kept = w.kept[min(w.start, len(w.kept)):]
+	}
+	records := make([]models.Record, len(w.kept))
+	for i, ranked := range w.kept {
+		records[i] = ranked.record
+	}
+	return records
+}
+
+func (w *recordWindow) compare(a, b rankedRecord) int {
+	for _, field := range w.sort {
+		if result := sortKeys[field.Field](a.record, b.record); result != 0 {
+			if field.Descending {
+				return -result
+			}
+			return result
+		}
+	}
+	return cmp.Compare(a.seq, b.seq)
+}
+
+// heap.Interface, ordered worst first so the root is the match to evict.
+func (w *recordWindow) Len() int           { return len(w.kept) }
+func (w *recordWindow) Less(i, j int) bool { return w.compare(w.kept[i], w.kept[j]) > 0 }
+func (w *recordWindow) Swap(i, j int)      { w.kept[i], w.kept[j] = w.kept[j], w.kept[i] }
+func (w *recordWindow) Push(x any)         { w.kept = append(w.kept, x.(rankedRecord)) }
+func (w *recordWindow) Pop() any {
+	last := w.kept[len(w.kept)-1]
+	w.kept = w.kept[:len(w.kept)-1]
+	return last
+}
+
+func containsFold(lowerNeedle string, values ...string) bool {
+	for _, value := range values {
+		if strings.Contains(strings.ToLower(value), lowerNeedle) {
+			return true
+		}
+	}
+	return false
+}
+
+// SearchRecords runs a ranked full-text query over names, address, email and
+// employer fields, returning up to limit hits and the total number of matches.
+func (s *RecordService) SearchRecords(q string, limit int) ([]repository.SearchHit, int, error) {
+	if strings.TrimSpace(q) == "" {
+		return nil, 0, fmt.Errorf("%w: search query must not be empty", ErrInvalidQuery)
+	}
+	if limit < 0 {
+		return nil, 0, fmt.Errorf("%w: limit must not be negative", ErrInvalidQuery)
+	}
+	hits, total := s.store.Search(q, limit)
+	return hits, total, nil
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"slices"
+	"testing"
+
+	"craft-fusion/craft-go/models"
+	"craft-fusion/craft-go/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newQueryTestService(t *testing.T) *RecordService {
+	t.Helper()
+	store := repository.NewMemoryStore()
+	require.NoError(t, store.Replace([]models.Record{
+		{UID: "1", FirstName: "Ann", LastName: "Smith", Address: models.Address{City: "Denver", State: "Colorado", Zipcode: "80202"}, TotalHouseholdIncome: 120000},
+		{UID: "2", FirstName: "Bob", LastName: "Jones", Address: models.Address{City: "Boulder", State: "Colorado", Zipcode: "80301"}, TotalHouseholdIncome: 90000},
+		{UID: "3", FirstName: "Cy", LastName: "Smith", Address: models.Address{City: "Burlington", State: "Vermont", Zipcode: "05401"}, TotalHouseholdIncome: 150000},
+		{UID: "4", FirstName: "Di", LastName: "Adams", Address: models.Address{City: "Denver", State: "Colorado", Zipcode: "80202"}, TotalHouseholdIncome: 60000},
+	}))
+	return NewRecordService(store)
+}
+
+func recordUIDs(records []models.Record) []string {
+	uids := make([]string, len(records))
+	for i, record := range records {
+		uids[i] = record.UID
+	}
+	return uids
+}
+
+func TestQueryRecordsFiltersSortsAndPages(t *testing.T) {
+	t.Parallel()
+	service := newQueryTestService(t)
+	minIncome := 80000.0
+
+	tests := []struct {
+		name  string
+		query RecordQuery
+		want  []string
+		total int
+	}{
+		{"all records in dataset order", RecordQuery{}, []string{"1", "2", "3", "4"}, 4},
+		{"state filter uses index", RecordQuery{State: "colorado"}, []string{"1", "2", "4"}, 3},
+		{"city and min income", RecordQuery{City: "Denver", MinIncome: &minIncome}, []string{"1"}, 1},
+		{"free text", RecordQuery{Q: "bur"}, []string{"3"}, 1},
+		{"multi-field sort", RecordQuery{Sort: []SortField{{Field: "lastName"}, {Field: "totalHouseholdIncome", Descending: true}}}, []string{"4", "2", "3", "1"}, 4},
+		{"second page", RecordQuery{Page: 2, PageSize: 3, Sort: []SortField{{Field: "UID"}}}, []string{"4"}, 4},
+		{"limit caps unpaged results", RecordQuery{Limit: 2}, []string{"1", "2"}, 4},
+		{"limit ignored when paging", RecordQuery{Limit: 1, PageSize: 2}, []string{"1", "2"}, 4},
+		{"offset skips unpaged results", RecordQuery{Offset: 1, Limit: 2, Sort: []SortField{{Field: "lastName"}}}, []string{"2", "1"}, 4},
+		{"offset ignored when paging", RecordQuery{Offset: 3, PageSize: 1}, []string{"1"}, 4},
+		{"page past the end", RecordQuery{Page: 5, PageSize: 3}, []string{}, 4},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page, err := service.QueryRecords(test.query)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, recordUIDs(page.Records))
+			assert.Equal(t, test.total, page.Total)
+		})
+	}
+}
+
+func TestRecordWindowMatchesAFullStableSort(t *testing.T) {
+	t.Parallel()
+	records := repository.GenerateMockRecords(500, 7)
+	for i := range records {
+		records[i].Address.State = []string{"Colorado", "Vermont", "Ohio"}[i%3]
+	}
+	sort := []SortField{{Field: "state"}, {Field: "totalHouseholdIncome", Descending: true}}
+	want := slices.Clone(records)
+	window := newRecordWindow(0, 0, sort)
+	slices.SortStableFunc(want, func(a, b models.Record) int {
+		return window.compare(rankedRecord{record: a}, rankedRecord{record: b})
+	})
+
+	for _, bounds := range [][2]int{{0, 10}, {40, 60}, {490, 510}, {0, 0}} {
+		window := newRecordWindow(bounds[0], bounds[1], sort)
+		for _, record := range records {
+			window.add(record)
+			if bounds[1] > 0 {
+				require.LessOrEqual(t, len(window.kept), bounds[1], "a bounded window keeps at most end matches")
+			}
+		}
+		end := len(want)
+		if bounds[1] > 0 {
+			end = min(bounds[1], end)
+		}
+		assert.Equal(t, recordUIDs(want[bounds[0]:end]), recordUIDs(window.records()), bounds)
+		assert.Equal(t, len(records), window.total)
+	}
+
+	unsorted := newRecordWindow(100, 110, nil)
+	for _, record := range records {
+		unsorted.add(record)
+	}
+	assert.Len(t, unsorted.kept, 10, "unsorted windows keep only the requested records")
+	assert.Equal(t, recordUIDs(records[100:110]), recordUIDs(unsorted.records()))
+}
+
+func TestQueryRecordsRejectsInvalidQueries(t *testing.T) {
+	t.Parallel()
+	service := newQueryTestService(t)
+
+	_, err := ParseSort("lastName,-password")
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+
+	_, err = service.QueryRecords(RecordQuery{PageSize: -1})
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+	_, err = service.QueryRecords(RecordQuery{PageSize: MaxPageSize + 1})
+	assert.ErrorIs(t, err, ErrPageTooLarge)
+	for _, sort := range [][]SortField{nil, {{Field: "lastName"}}} {
+		_, err = service.QueryRecords(RecordQuery{Page: 1 << 62, PageSize: 4, Sort: sort})
+		assert.ErrorIs(t, err, ErrInvalidQuery)
+	}
+}
+
+func TestSearchRecordsValidatesQuery(t *testing.T) {
+	t.Parallel()
+	service := newQueryTestService(t)
+
+	hits, total, err := service.SearchRecords("smi col", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "1", hits[0].Record.UID)
+
+	_, _, err = service.SearchRecords("  ", 10)
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+	_, _, err = service.SearchRecords("smith", -1)
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
@@ -1,16 +1,218 @@
 package services
 
 import (
+	"craft-fusion/craft-go/generator"
 	"craft-fusion/craft-go/models"
 	"craft-fusion/craft-go/repository"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
 )
 
-// GetRecords retrieves mock records.
-func GetRecords(limit int) []models.Record {
-	return repository.GenerateMockRecords(limit)
+// ErrRecordExists is returned when creating a record whose UID is already stored.
+var ErrRecordExists = repository.NewError(repository.KindConflict, "record already exists")
+
+// RecordService exposes record operations on top of a RecordStore.
+type RecordService struct {
+	store      *repository.ObservedStore
+	stats      *GenerationStats
+	profiles   *generator.Registry
+	aggregates aggregateCache
+	jobs       generationJobs
+	// writeMu makes the existence checks in the mutating methods atomic with
+	// the write that follows them, and keeps dataset replacements from
+	// landing between the two.
+	writeMu sync.Mutex
+}
+
+// NewRecordService returns a RecordService backed by the given store that
+// keeps the default number of generation runs.
+func NewRecordService(store repository.RecordStore) *RecordService {
+	return NewRecordServiceWithStats(store, NewGenerationStats(DefaultGenerationHistory))
+}
+
+// NewRecordServiceWithStats returns a RecordService that records generation
+// runs into stats. Writes made through the service are published to a change
+// feed; see SubscribeChanges.
+func NewRecordServiceWithStats(store repository.RecordStore, stats *GenerationStats) *RecordService {
+	return &RecordService{store: repository.Observe(store, repository.NewChangeFeed()), stats: stats, profiles: generator.Builtin()}
+}
+
+// SubscribeChanges returns a subscription to the changes made to the dataset
+// through this service, buffering up to size changes.
+func (s *RecordService) SubscribeChanges(filter repository.ChangeFilter, size int) *repository.Subscription {
+	return s.store.Feed().Subscribe(filter, size)
+}
+
+// SetProfiles replaces the built-in generator profiles. Call it before the
+// service handles requests.
+func (s *RecordService) SetProfiles(profiles *generator.Registry) {
+	s.profiles = profiles
+}
+
+// Profiles returns the generator profiles selectable by name.
+func (s *RecordService) Profiles() *generator.Registry {
+	return s.profiles
+}
+
+// Stats returns the generation history shared by every generation path.
+func (s *RecordService) Stats() *GenerationStats {
+	return s.stats
+}
+
+// GenerateRecords generates count mock records from seed with the named
+// profile ("" for the default) and locale ("" for none) without storing them,
+// and records the run in the generation history.
+func (s *RecordService) GenerateRecords(count int, seed int64, profile, locale string) ([]models.Record, models.GenerationRun, error) {
+	return s.generate(SourceGenerate, count, seed, profile, locale)
+}
+
+// RegenerateRecords replaces the stored dataset with count mock records
+// generated from seed with the named profile ("" for the default) and locale
+// ("" for none). Listing never regenerates; this and generation jobs are the
+// only ways to reseed. Pass a seed from repository.ResolveSeed to be able to
+// replay it.
+func (s *RecordService) RegenerateRecords(count int, seed int64, profile, locale string) ([]models.Record, error) {
+	records, _, err := s.generate(SourceSeed, count, seed, profile, locale)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.replace(records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// replace swaps in records as the whole dataset under writeMu.
+func (s *RecordService) replace(records []models.Record) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.store.Replace(records)
+}
+
+func (s *RecordService) generate(source string, count int, seed int64, name, tag string) ([]models.Record, models.GenerationRun, error) {
+	profile, err := s.profile(name, tag)
+	if err != nil {
+		return nil, models.GenerationRun{}, err
+	}
+	start := time.Now()
+	records := repository.GenerateRecords(profile, count, seed)
+	return records, s.stats.Observe(source, len(records), seed, start), nil
+}
+
+// profile resolves a profile name and locale tag, either of which may be "".
+func (s *RecordService) profile(name, tag string) (*generator.Profile, error) {
+	profile, err := s.profiles.Get(name)
+	if err != nil {
+		return nil, invalidParameter("profile", err)
+	}
+	if tag == "" {
+		return profile, nil
+	}
+	locale, err := generator.LookupLocale(tag)
+	if err != nil {
+		return nil, invalidParameter("locale", err)
+	}
+	return profile.WithLocale(locale), nil
+}
+
+// invalidParameter reports err, such as an unknown profile, as a validation
+// error of the named generation parameter. errors.Is still matches err.
+func invalidParameter(name string, err error) error {
+	return &repository.Error{
+		Kind:    repository.KindValidation,
+		Message: err.Error(),
+		Fields:  []repository.FieldError{{Field: name, Message: err.Error()}},
+		Err:     err,
+	}
+}
+
+// SeedIfEmpty generates count records from seed with the default profile when
+// the store holds none, so a persisted dataset survives restarts. It reports
+// whether it seeded.
+func (s *RecordService) SeedIfEmpty(count int, seed int64) (bool, error) {
+	if count <= 0 || s.store.Count() > 0 {
+		return false, nil
+	}
+	_, err := s.RegenerateRecords(count, seed, "", "")
+	return err == nil, err
+}
+
+// GetRecordByUID retrieves a stored record by UID.
+func (s *RecordService) GetRecordByUID(uid string) (models.Record, error) {
+	return s.store.Get(uid)
+}
+
+// TotalIncome sums the annual salaries of every company on the record with
+// the given UID, matching the NestJS RecordsService.calculateTotalIncome.
+func (s *RecordService) TotalIncome(uid string) (float64, error) {
+	record, err := s.store.Get(uid)
+	if err != nil {
+		return 0, err
+	}
+	var total float64
+	for _, company := range record.Salary {
+		total += company.AnnualSalary
+	}
+	return total, nil
 }
 
-// GetRecordByUID retrieves a mock record by UID.
-func GetRecordByUID(uid string) (models.Record, error) {
-	return repository.FindRecordByUID(uid)
+// CreateRecord stores a new record, assigning a UID when none is provided.
+func (s *RecordService) CreateRecord(record models.Record) (models.Record, error) {
+	if record.UID == "" {
+		record.UID = gofakeit.UUID()
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if _, err := s.store.Get(record.UID); err == nil {
+		return models.Record{}, ErrRecordExists
+	} else if !errors.Is(err, repository.ErrRecordNotFound) {
+		return models.Record{}, err
+	}
+	if err := s.store.Put(record); err != nil {
+		return models.Record{}, err
+	}
+	return record, nil
+}
+
+// UpdateRecord replaces the stored record with the given UID.
+func (s *RecordService) UpdateRecord(uid string, record models.Record) (models.Record, error) {
+	return s.PatchRecord(uid, func(existing *models.Record) error {
+		*existing = record
+		return nil
+	})
+}
+
+// PatchRecord applies patch to a copy of the stored record with the given UID
+// and stores the result, leaving the stored record untouched when patch
+// fails. The UID cannot be changed by the patch.
+func (s *RecordService) PatchRecord(uid string, patch func(*models.Record) error) (models.Record, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	stored, err := s.store.Get(uid)
+	if err != nil {
+		return models.Record{}, err
+	}
+	record := stored.Clone()
+	if err := patch(&record); err != nil {
+		return models.Record{}, err
+	}
+	record.UID = uid
+	if err := s.store.Put(record); err != nil {
+		return models.Record{}, err
+	}
+	return record, nil
+}
+
+// DeleteRecord removes the stored record with the given UID.
+func (s *RecordService) DeleteRecord(uid string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	return s.store.Delete(uid)
 }
@@ -3,22 +3,112 @@ package services
 import (
 	"testing"
 
+	"craft-fusion/craft-go/models"
+	"craft-fusion/craft-go/repository"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func TestGetRecordsAndGetRecordByUID(t *testing.T) {
-	records := GetRecords(4)
+func TestRegenerateRecordsAndGetRecordByUID(t *testing.T) {
+	t.Parallel()
+	service := NewRecordService(repository.NewMemoryStore())
+
+	records, err := service.RegenerateRecords(4, 0, "", "")
+	require.NoError(t, err)
 	require.Len(t, records, 4)
 
-	record, err := GetRecordByUID(records[2].UID)
+	record, err := service.GetRecordByUID(records[2].UID)
 	require.NoError(t, err)
 	assert.Equal(t, records[2], record)
 }
 
 func TestGetRecordByUIDReturnsErrorForUnknownRecord(t *testing.T) {
-	GetRecords(1)
+	t.Parallel()
+	service := NewRecordService(repository.NewMemoryStore())
+	_, err := service.RegenerateRecords(1, 0, "", "")
+	require.NoError(t, err)
 
-	_, err := GetRecordByUID("unknown")
+	_, err = service.GetRecordByUID("unknown")
+	assert.ErrorIs(t, err, repository.ErrRecordNotFound)
 	assert.EqualError(t, err, "record not found")
 }
+
+func TestSeedIfEmptyKeepsExistingDataset(t *testing.T) {
+	t.Parallel()
+	service := NewRecordService(repository.NewMemoryStore())
+
+	seeded, err := service.SeedIfEmpty(3, 0)
+	require.NoError(t, err)
+	assert.True(t, seeded)
+	first, err := service.QueryRecords(RecordQuery{})
+	require.NoError(t, err)
+	require.Len(t, first.Records, 3)
+
+	seeded, err = service.SeedIfEmpty(5, 0)
+	require.NoError(t, err)
+	assert.False(t, seeded)
+	second, err := service.QueryRecords(RecordQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, first.Records, second.Records)
+}
+
+func TestRecordServiceCRUD(t *testing.T) {
+	t.Parallel()
+	service := NewRecordService(repository.NewMemoryStore())
+
+	created, err := service.CreateRecord(models.Record{FirstName: "Ada", LastName: "Lovelace"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.UID)
+
+	_, err = service.CreateRecord(created)
+	assert.ErrorIs(t, err, ErrRecordExists)
+	kind, _ := repository.KindOf(err)
+	assert.Equal(t, repository.KindConflict, kind)
+
+	err = ValidateRecord(models.Record{LastName: "Lovelace"})
+	var domainErr *repository.Error
+	require.ErrorAs(t, err, &domainErr)
+	assert.Equal(t, repository.KindValidation, domainErr.Kind)
+	assert.Equal(t, "invalid record: firstName is required", domainErr.Message)
+	assert.Equal(t, []repository.FieldError{{Field: "firstName", Message: "is required"}}, domainErr.Fields)
+
+	updated, err := service.UpdateRecord(created.UID, models.Record{FirstName: "Augusta", LastName: "King"})
+	require.NoError(t, err)
+	assert.Equal(t, created.UID, updated.UID)
+	assert.Equal(t, "Augusta", updated.FirstName)
+
+	patched, err := service.PatchRecord(created.UID, func(record *models.Record) error {
+		record.Address.City = "London"
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Augusta", patched.FirstName)
+	assert.Equal(t, "London", patched.Address.City)
+
+	stored, err := service.GetRecordByUID(created.UID)
+	require.NoError(t, err)
+	assert.Equal(t, patched, stored)
+
+	require.NoError(t, service.DeleteRecord(created.UID))
+	assert.ErrorIs(t, service.DeleteRecord(created.UID), repository.ErrRecordNotFound)
+	_, err = service.UpdateRecord(created.UID, models.Record{FirstName: "Ada", LastName: "Lovelace"})
+	assert.ErrorIs(t, err, repository.ErrRecordNotFound)
+}
+
+func TestTotalIncomeSumsCompanySalaries(t *testing.T) {
+	t.Parallel()
+	store := repository.NewMemoryStore()
+	require.NoError(t, store.Replace([]models.Record{{
+		UID:    "income",
+		Salary: []models.Company{{AnnualSalary: 1000.25}, {AnnualSalary: 500}},
+	}}))
+	service := NewRecordService(store)
+
+	total, err := service.TotalIncome("income")
+	require.NoError(t, err)
+	assert.Equal(t, 1500.25, total)
+
+	_, err = service.TotalIncome("missing")
+	assert.ErrorIs(t, err, repository.ErrRecordNotFound)
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"craft-fusion/craft-go/models"
+	"craft-fusion/craft-go/repository"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// recordValidator checks records against the same `binding` struct tags the
+// HTTP layer enforces when decoding request bodies.
+var recordValidator = func() *validator.Validate {
+	validate := validator.New()
+	validate.SetTagName("binding")
+	validate.RegisterTagNameFunc(JSONFieldName)
+	return validate
+}()
+
+// ValidateRecord checks a record that did not arrive through request binding,
+// such as an imported row. It returns a validation error wrapping
+// validator.ValidationErrors on failure.
+func ValidateRecord(record models.Record) error {
+	if err := recordValidator.Struct(record); err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			return NewValidationError(validationErrs)
+		}
+		return err
+	}
+	return nil
+}
+
+// NewValidationError describes failed record validation as a domain error
+// listing each failed field.
+func NewValidationError(errs validator.ValidationErrors) *repository.Error {
+	fields := make([]repository.FieldError, len(errs))
+	for i, fieldErr := range errs {
+		fields[i].Field, fields[i].Message = describeFieldError(fieldErr)
+	}
+	return &repository.Error{
+		Kind:    repository.KindValidation,
+		Message: "invalid record: " + ValidationMessage(errs),
+		Fields:  fields,
+		Err:     errs,
+	}
+}
+
+// JSONFieldName names a struct field by its JSON key so validation errors use
+// the field names clients send.
+func JSONFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" || name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// ValidationMessage describes each failed field of a validator error, for
+// example "firstName is required; salary[0].annualSalary must be gte=0".
+func ValidationMessage(errs validator.ValidationErrors) string {
+	messages := make([]string, len(errs))
+	for i, fieldErr := range errs {
+		field, message := describeFieldError(fieldErr)
+		messages[i] = field + " " + message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// describeFieldError returns the path of a failed field below the record and
+// what it must be, such as "salary[0].annualSalary" and "must be gte=0".
+func describeFieldError(fieldErr validator.FieldError) (string, string) {
+	field := fieldErr.Namespace()
+	if dot := strings.Index(field, "."); dot >= 0 {
+		field = field[dot+1:]
+	}
+	switch {
+	case fieldErr.Tag() == "required":
+		return field, "is required"
+	case fieldErr.Param() != "":
+		return field, fmt.Sprintf("must be %s=%s", fieldErr.Tag(), fieldErr.Param())
+	default:
+		return field, fmt.Sprintf("must be a valid %s", fieldErr.Tag())
+	}
+}
@@ -0,0 +1,86 @@
+import { INestApplication } from '@nestjs/common';
+import { Test, TestingModule } from '@nestjs/testing';
+import * as fs from 'fs';
+import * as path from 'path';
+import { RecordsController } from './records.controller';
+import { RecordsService } from './records.service';
+import { Record } from './entities/record.interface';
+
+// The fixture is shared with craft-go, which replays the same cases against
+// its own routes (apps/craft-go/handlers/contract_test.go).
+const fixturePath = path.join(__dirname, '../../../../craft-go/handlers/testdata/nest_records_contract.json');
+
+// A documented response. Body is matched as a JSON subset, keys only requires
+// the listed top-level fields and length checks the size of an array body.
+interface ContractExpectation {
+  status: number;
+  body?: unknown;
+  keys?: string[];
+  length?: number;
+}
+
+interface ContractCase {
+  name: string;
+  method?: string;
+  paths: string[];
+  nest: ContractExpectation;
+}
+
+interface ContractFixture {
+  dataset: Record[];
+  cases: ContractCase[];
+}
+
+const fixture: ContractFixture = JSON.parse(fs.readFileSync(fixturePath, 'utf8'));
+
+// Only the paths served by NestJS; the /api-go ones belong to craft-go.
+const nestCases = fixture.cases.flatMap(contract =>
+  contract.paths.filter(p => p.startsWith('/api/')).map(p => ({ ...contract, path: p })),
+);
+
+describe('RecordsController contract', () => {
+  let app: INestApplication;
+  let baseUrl: string;
+
+  beforeAll(async () => {
+    const module: TestingModule = await Test.createTestingModule({
+      controllers: [RecordsController],
+      providers: [RecordsService],
+    }).compile();
+
+    module.get<RecordsService>(RecordsService)['mockDatabase'] = fixture.dataset;
+
+    app = module.createNestApplication({ logger: false });
+    app.setGlobalPrefix('api');
+    await app.listen(0);
+    baseUrl = (await app.getUrl()).replace('[::1]', 'localhost');
+  });
+
+  afterAll(async () => {
+    await app.close();
+  });
+
+  it('has NestJS cases to replay', () => {
+    expect(nestCases.length).toBeGreaterThan(0);
+  });
+
+  it.each(nestCases)('$name $path', async ({ method, path: requestPath, nest }) => {
+    const response = await fetch(baseUrl + requestPath, { method: method ?? 'GET' });
+    expect(response.status).toBe(nest.status);
+
+    const body = await response.json();
+    if (nest.body !== undefined) {
+      if (nest.body !== null && typeof nest.body === 'object') {
+        expect(body).toMatchObject(nest.body as object);
+      } else {
+        expect(body).toEqual(nest.body);
+      }
+    }
+    for (const key of nest.keys ?? []) {
+      expect(body).toHaveProperty(key);
+    }
+    if (nest.length !== undefined) {
+      expect(body).toHaveLength(nest.length);
+    }
+  });
+});